@@ -0,0 +1,132 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_log
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ListAuditLogsRequest 查询审计日志请求
+type ListAuditLogsRequest struct {
+	Page        int        `json:"page" form:"page" binding:"min=1"`
+	PageSize    int        `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	ActorUserID uint64     `json:"actor_user_id" form:"actor_user_id"`
+	Action      string     `json:"action" form:"action" binding:"max=128"`
+	TargetType  string     `json:"target_type" form:"target_type" binding:"max=64"`
+	TargetID    string     `json:"target_id" form:"target_id" binding:"max=64"`
+	TraceID     string     `json:"trace_id" form:"trace_id" binding:"max=32"`
+	StartTime   *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
+	EndTime     *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
+}
+
+// ListAuditLogsResponse 查询审计日志响应
+type ListAuditLogsResponse struct {
+	Total     int64            `json:"total"`
+	Page      int              `json:"page"`
+	PageSize  int              `json:"page_size"`
+	AuditLogs []model.AuditLog `json:"audit_logs"`
+}
+
+// ListAuditLogs 获取审计日志列表
+// @Tags admin
+// @Produce json
+// @Param request query ListAuditLogsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/audit-logs [get]
+func ListAuditLogs(c *gin.Context) {
+	var req ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.AuditLog{})
+
+	if req.ActorUserID > 0 {
+		baseQuery = baseQuery.Where("actor_user_id = ?", req.ActorUserID)
+	}
+	if req.Action != "" {
+		baseQuery = baseQuery.Where("action ILIKE ?", util.ContainsPattern(req.Action))
+	}
+	if req.TargetType != "" {
+		baseQuery = baseQuery.Where("target_type = ?", req.TargetType)
+	}
+	if req.TargetID != "" {
+		baseQuery = baseQuery.Where("target_id = ?", req.TargetID)
+	}
+	if req.TraceID != "" {
+		baseQuery = baseQuery.Where("trace_id = ?", req.TraceID)
+	}
+	if req.StartTime != nil {
+		baseQuery = baseQuery.Where("created_at >= ?", req.StartTime)
+	}
+	if req.EndTime != nil {
+		baseQuery = baseQuery.Where("created_at <= ?", req.EndTime)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListAuditLogsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Order("created_at DESC").
+		Order("id DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&response.AuditLogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetAuditLog 获取单条审计日志
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "审计日志ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/audit-logs/{id} [get]
+func GetAuditLog(c *gin.Context) {
+	var auditLog model.AuditLog
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&auditLog).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(AuditLogNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(auditLog))
+}
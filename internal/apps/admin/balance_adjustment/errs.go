@@ -0,0 +1,25 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balance_adjustment
+
+const (
+	AdjustmentNotFound      = "余额调整单不存在"
+	AdjustmentNotPending    = "余额调整单已处理"
+	CannotApproveOwnRequest = "不能审批自己发起的余额调整"
+	TargetUserNotFound      = "调整用户不存在"
+	InvalidAdjustmentID     = "余额调整单ID格式错误"
+)
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balance_adjustment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequiresApproval 判断调整金额是否超过审批阈值
+func RequiresApproval(ctx context.Context, amount decimal.Decimal) (bool, error) {
+	threshold, err := model.GetIntByKey(ctx, model.ConfigKeyAdjustmentApprovalThreshold)
+	if err != nil {
+		return false, err
+	}
+	return amount.GreaterThan(decimal.NewFromInt(int64(threshold))), nil
+}
+
+// Create 创建余额调整单，未超过审批阈值时直接入账
+func Create(ctx context.Context, adjustment *model.BalanceAdjustment) error {
+	requiresApproval, err := RequiresApproval(ctx, adjustment.Amount)
+	if err != nil {
+		return err
+	}
+
+	if err := db.TransactionOnce(ctx, func(tx *gorm.DB) error {
+		var user model.User
+		if err := user.GetByID(tx, adjustment.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(TargetUserNotFound)
+			}
+			return err
+		}
+
+		adjustment.Status = model.BalanceAdjustmentStatusPending
+		if err := tx.Create(adjustment).Error; err != nil {
+			return err
+		}
+
+		if requiresApproval {
+			return nil
+		}
+
+		// 小额调整由发起人直接入账
+		return post(tx, adjustment, adjustment.RequesterUserID, "")
+	}); err != nil {
+		return err
+	}
+
+	if adjustment.Status == model.BalanceAdjustmentStatusApproved {
+		model.InvalidateUserCache(ctx, adjustment.UserID)
+	}
+	return nil
+}
+
+// Approve 审批通过余额调整单并入账，审批人不能是发起人
+func Approve(ctx context.Context, adjustmentID uint64, approverUserID uint64, remark string) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	if err := db.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := lockPending(tx, adjustmentID, &adjustment); err != nil {
+			return err
+		}
+		if adjustment.RequesterUserID == approverUserID {
+			return errors.New(CannotApproveOwnRequest)
+		}
+		return post(tx, &adjustment, approverUserID, remark)
+	}); err != nil {
+		return nil, err
+	}
+
+	model.InvalidateUserCache(ctx, adjustment.UserID)
+	return &adjustment, nil
+}
+
+// Reject 驳回余额调整单
+func Reject(ctx context.Context, adjustmentID uint64, approverUserID uint64, remark string) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	if err := db.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := lockPending(tx, adjustmentID, &adjustment); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		adjustment.Status = model.BalanceAdjustmentStatusRejected
+		adjustment.ApproverUserID = approverUserID
+		adjustment.ReviewRemark = remark
+		adjustment.ReviewedAt = &now
+		return tx.Model(&adjustment).Updates(map[string]interface{}{
+			"status":           adjustment.Status,
+			"approver_user_id": adjustment.ApproverUserID,
+			"review_remark":    adjustment.ReviewRemark,
+			"reviewed_at":      adjustment.ReviewedAt,
+		}).Error
+	}); err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+// lockPending 锁定待审批的余额调整单
+func lockPending(tx *gorm.DB, adjustmentID uint64, adjustment *model.BalanceAdjustment) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Where("id = ?", adjustmentID).
+		First(adjustment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(AdjustmentNotFound)
+		}
+		return err
+	}
+	if adjustment.Status != model.BalanceAdjustmentStatusPending {
+		return errors.New(AdjustmentNotPending)
+	}
+	return nil
+}
+
+// post 生成调整订单并变更用户余额，系统（用户 ID 为 0）作为对手方
+func post(tx *gorm.DB, adjustment *model.BalanceAdjustment, approverUserID uint64, remark string) error {
+	var user model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Where("id = ?", adjustment.UserID).
+		First(&user).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	order := model.Order{
+		OrderName: "余额调整",
+		Amount:    adjustment.Amount,
+		Status:    model.OrderStatusSuccess,
+		Type:      model.OrderTypeAdjustment,
+		Remark:    adjustment.Reason,
+		TradeTime: now,
+		ExpiresAt: now,
+	}
+
+	var balanceExpr clause.Expr
+	switch adjustment.Direction {
+	case model.BalanceAdjustmentDirectionCredit:
+		order.PayeeUserID = user.ID
+		balanceExpr = gorm.Expr("available_balance + ?", adjustment.Amount)
+	case model.BalanceAdjustmentDirectionDebit:
+		if user.AvailableBalance.LessThan(adjustment.Amount) {
+			return errors.New(common.InsufficientBalance)
+		}
+		order.PayerUserID = user.ID
+		balanceExpr = gorm.Expr("available_balance - ?", adjustment.Amount)
+	default:
+		return fmt.Errorf("未知的调整方向: %s", adjustment.Direction)
+	}
+
+	if err := tx.Create(&order).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", user.ID).
+		UpdateColumn("available_balance", balanceExpr).Error; err != nil {
+		return err
+	}
+
+	adjustment.Status = model.BalanceAdjustmentStatusApproved
+	adjustment.ApproverUserID = approverUserID
+	adjustment.ReviewRemark = remark
+	adjustment.OrderID = &order.ID
+	adjustment.ReviewedAt = &now
+	return tx.Model(adjustment).Updates(map[string]interface{}{
+		"status":           adjustment.Status,
+		"approver_user_id": adjustment.ApproverUserID,
+		"review_remark":    adjustment.ReviewRemark,
+		"order_id":         adjustment.OrderID,
+		"reviewed_at":      adjustment.ReviewedAt,
+	}).Error
+}
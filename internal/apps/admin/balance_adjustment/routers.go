@@ -0,0 +1,229 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balance_adjustment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+)
+
+// CreateAdjustmentRequest 创建余额调整请求
+type CreateAdjustmentRequest struct {
+	UserID    uint64          `json:"user_id" binding:"required"`
+	Direction string          `json:"direction" binding:"required,oneof=credit debit"`
+	Amount    decimal.Decimal `json:"amount" binding:"required"`
+	Reason    string          `json:"reason" binding:"required,max=255"`
+}
+
+// CreateAdjustment 创建余额调整，超过审批阈值时需其他管理员审批
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateAdjustmentRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/balance-adjustments [post]
+func CreateAdjustment(c *gin.Context) {
+	var req CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	adjustment := model.BalanceAdjustment{
+		UserID:          req.UserID,
+		Direction:       model.BalanceAdjustmentDirection(req.Direction),
+		Amount:          req.Amount,
+		Reason:          req.Reason,
+		RequesterUserID: currentUser.ID,
+	}
+
+	if err := Create(c.Request.Context(), &adjustment); err != nil {
+		switch err.Error() {
+		case TargetUserNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case common.InsufficientBalance:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		case common.Busy:
+			c.JSON(http.StatusConflict, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetBalanceAdjustment, adjustment.ID)
+	audit.SetAfter(c, adjustment)
+
+	c.JSON(http.StatusOK, util.OK(adjustment))
+}
+
+// ListAdjustmentsRequest 查询余额调整列表请求
+type ListAdjustmentsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	UserID   uint64 `json:"user_id" form:"user_id"`
+}
+
+// ListAdjustmentsResponse 查询余额调整列表响应
+type ListAdjustmentsResponse struct {
+	Total       int64                     `json:"total"`
+	Page        int                       `json:"page"`
+	PageSize    int                       `json:"page_size"`
+	Adjustments []model.BalanceAdjustment `json:"adjustments"`
+}
+
+// ListAdjustments 获取余额调整列表
+// @Tags admin
+// @Produce json
+// @Param request query ListAdjustmentsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/balance-adjustments [get]
+func ListAdjustments(c *gin.Context) {
+	var req ListAdjustmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.BalanceAdjustment{}).
+		Select("balance_adjustments.*, target_user.username as username, requester_user.username as requester_username, approver_user.username as approver_username").
+		Joins("LEFT JOIN users as target_user ON balance_adjustments.user_id = target_user.id").
+		Joins("LEFT JOIN users as requester_user ON balance_adjustments.requester_user_id = requester_user.id").
+		Joins("LEFT JOIN users as approver_user ON balance_adjustments.approver_user_id = approver_user.id")
+
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("balance_adjustments.status = ?", model.BalanceAdjustmentStatus(req.Status))
+	}
+	if req.UserID > 0 {
+		baseQuery = baseQuery.Where("balance_adjustments.user_id = ?", req.UserID)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListAdjustmentsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Order("balance_adjustments.created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&response.Adjustments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ReviewAdjustmentRequest 审批余额调整请求
+type ReviewAdjustmentRequest struct {
+	Remark string `json:"remark" binding:"max=255"`
+}
+
+// ApproveAdjustment 审批通过余额调整
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "余额调整单ID"
+// @Param request body ReviewAdjustmentRequest false "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/balance-adjustments/{id}/approve [post]
+func ApproveAdjustment(c *gin.Context) {
+	reviewAdjustment(c, Approve)
+}
+
+// RejectAdjustment 驳回余额调整
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "余额调整单ID"
+// @Param request body ReviewAdjustmentRequest false "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/balance-adjustments/{id}/reject [post]
+func RejectAdjustment(c *gin.Context) {
+	reviewAdjustment(c, Reject)
+}
+
+// reviewAdjustment 处理审批请求
+func reviewAdjustment(c *gin.Context, review func(ctx context.Context, adjustmentID uint64, approverUserID uint64, remark string) (*model.BalanceAdjustment, error)) {
+	adjustmentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidAdjustmentID))
+		return
+	}
+
+	var req ReviewAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	audit.SetTarget(c, audit.TargetBalanceAdjustment, adjustmentID)
+
+	adjustment, err := review(c.Request.Context(), adjustmentID, currentUser.ID, req.Remark)
+	if err != nil {
+		switch err.Error() {
+		case AdjustmentNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case AdjustmentNotPending, CannotApproveOwnRequest, common.InsufficientBalance:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		case common.Busy:
+			c.JSON(http.StatusConflict, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetAfter(c, adjustment)
+
+	c.JSON(http.StatusOK, util.OK(adjustment))
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_stat
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/cache"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// ListCacheStats 获取当前实例进程内缓存的命中统计，统计自进程启动起累计，各实例独立
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/caches [get]
+func ListCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, util.OK(cache.AllStats()))
+}
@@ -19,6 +19,7 @@ package admin
 import (
 	"net/http"
 
+	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/otel_trace"
@@ -28,6 +29,7 @@ import (
 	"github.com/linux-do/pay/internal/apps/oauth"
 )
 
+// LoginAdminRequired 要求当前用户至少拥有一个管理角色，并加载其权限
 func LoginAdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// init trace
@@ -36,7 +38,13 @@ func LoginAdminRequired() gin.HandlerFunc {
 
 		user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-		if !user.IsAdmin {
+		permissions, err := model.GetPermissionsByUserID(db.DB(ctx), user.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
+			return
+		}
+
+		if len(permissions) == 0 {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error_msg": AdminRequired, "data": nil})
 			return
 		}
@@ -44,7 +52,24 @@ func LoginAdminRequired() gin.HandlerFunc {
 		// log
 		logger.InfoF(ctx, "[LoginAdminRequired] %d %s", user.ID, user.Username)
 
+		// set permissions
+		util.SetToContext(c, PermissionsObjKey, permissions)
+
 		// next
 		c.Next()
 	}
 }
+
+// RequirePermission 要求当前管理员拥有指定权限，需在 LoginAdminRequired 之后使用
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, _ := util.GetFromContext[[]string](c, PermissionsObjKey)
+
+		if !model.HasPermission(permissions, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error_msg": PermissionDenied, "data": nil})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package periodic_task
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// PeriodicTaskResponse 周期任务信息
+type PeriodicTaskResponse struct {
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Cron          string     `json:"cron"`
+	Queue         string     `json:"queue"`
+	UniqueSeconds int64      `json:"unique_seconds"`
+	Enabled       bool       `json:"enabled"`
+	Timezone      string     `json:"timezone"`
+	NextRunAt     *time.Time `json:"next_run_at"`
+}
+
+// RunPeriodicTaskResponse 手动触发结果
+type RunPeriodicTaskResponse struct {
+	TaskID string `json:"task_id"`
+	Queue  string `json:"queue"`
+}
+
+// ListPeriodicTasks 获取周期任务列表及下次执行时间
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/schedules [get]
+func ListPeriodicTasks(c *gin.Context) {
+	location, err := schedule.Location()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	tasks, err := schedule.PeriodicTasks(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	now := time.Now()
+	items := make([]PeriodicTaskResponse, 0, len(tasks))
+	for i := range tasks {
+		item := PeriodicTaskResponse{
+			Name:          tasks[i].Name,
+			Description:   tasks[i].Description,
+			Cron:          tasks[i].Cron,
+			Queue:         tasks[i].Queue,
+			UniqueSeconds: int64(tasks[i].Unique / time.Second),
+			Enabled:       tasks[i].Enabled,
+			Timezone:      location.String(),
+		}
+		if tasks[i].Enabled {
+			if next, errNext := tasks[i].NextRun(now, location); errNext == nil {
+				item.NextRunAt = &next
+			}
+		}
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, util.OK(items))
+}
+
+// RunPeriodicTask 立即触发一次周期任务
+// @Tags admin
+// @Produce json
+// @Param name path string true "任务名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/schedules/{name}/run [post]
+func RunPeriodicTask(c *gin.Context) {
+	name := c.Param("name")
+
+	periodicTask, err := schedule.FindPeriodicTask(c.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, schedule.ErrPeriodicTaskNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PeriodicTaskNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	info, err := schedule.RunNow(c.Request.Context(), periodicTask)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetPeriodicTask, periodicTask.Name)
+	audit.SetAfter(c, gin.H{"task_id": info.ID, "queue": info.Queue})
+
+	c.JSON(http.StatusOK, util.OK(RunPeriodicTaskResponse{TaskID: info.ID, Queue: info.Queue}))
+}
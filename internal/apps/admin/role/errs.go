@@ -0,0 +1,25 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+const (
+	RoleNotFound         = "角色不存在"
+	RoleNameExists       = "角色名称已存在"
+	SystemRoleReadonly   = "内置角色不允许修改或删除"
+	PermissionExceeded   = "不能授予或修改超出自身权限的角色"
+	InvalidPermissionFmt = "无效的权限: %s"
+)
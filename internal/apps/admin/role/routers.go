@@ -0,0 +1,267 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=64"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+}
+
+// UpdateRoleRequest 更新角色请求
+type UpdateRoleRequest struct {
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+}
+
+// validatePermissions 校验权限是否可分配
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !model.IsValidPermission(permission) {
+			return fmt.Errorf(InvalidPermissionFmt, permission)
+		}
+	}
+	return nil
+}
+
+// canGrant 判断当前管理员是否拥有 permissions 中的全部权限
+func canGrant(c *gin.Context, permissions []string) (bool, error) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	granted, err := model.GetPermissionsByUserID(db.DB(db.WithPrimary(c.Request.Context())), currentUser.ID)
+	if err != nil {
+		return false, err
+	}
+	return model.CoversPermissions(granted, permissions), nil
+}
+
+// ListPermissions 获取可分配的权限列表
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/permissions [get]
+func ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, util.OK(model.Permissions))
+}
+
+// ListRoles 获取角色列表
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles [get]
+func ListRoles(c *gin.Context) {
+	var roles []model.Role
+	if err := db.DB(c.Request.Context()).
+		Order("id ASC").
+		Find(&roles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(roles))
+}
+
+// CreateRole 创建角色
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateRoleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles [post]
+func CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := validatePermissions(req.Permissions); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if ok, err := canGrant(c, req.Permissions); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	} else if !ok {
+		c.JSON(http.StatusForbidden, util.Err(PermissionExceeded))
+		return
+	}
+
+	// 检查角色名称是否已存在
+	var existing model.Role
+	if err := existing.GetByName(db.DB(c.Request.Context()), req.Name); err == nil {
+		c.JSON(http.StatusBadRequest, util.Err(RoleNameExists))
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	role := model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	if err := db.DB(c.Request.Context()).Create(&role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetRole, role.ID)
+	audit.SetAfter(c, role)
+
+	c.JSON(http.StatusOK, util.OK(role))
+}
+
+// UpdateRole 更新角色
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "角色ID"
+// @Param request body UpdateRoleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles/{id} [put]
+func UpdateRole(c *gin.Context) {
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := validatePermissions(req.Permissions); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var role model.Role
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RoleNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if role.IsSystem {
+		c.JSON(http.StatusBadRequest, util.Err(SystemRoleReadonly))
+		return
+	}
+
+	// 修改前后的权限都需在当前管理员的权限范围内，避免提升或削弱权限更高的角色
+	if ok, err := canGrant(c, slices.Concat(req.Permissions, role.Permissions)); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	} else if !ok {
+		c.JSON(http.StatusForbidden, util.Err(PermissionExceeded))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetRole, role.ID)
+	audit.SetBefore(c, role)
+
+	if err := db.DB(c.Request.Context()).
+		Model(&role).
+		Updates(map[string]interface{}{
+			"description": req.Description,
+			"permissions": util.StringArray(req.Permissions),
+		}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetAfter(c, role)
+
+	c.JSON(http.StatusOK, util.OK(role))
+}
+
+// DeleteRole 删除角色，同时移除已分配的用户
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "角色ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles/{id} [delete]
+func DeleteRole(c *gin.Context) {
+	var role model.Role
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RoleNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if role.IsSystem {
+		c.JSON(http.StatusBadRequest, util.Err(SystemRoleReadonly))
+		return
+	}
+
+	// 删除角色会撤销其持有者的权限，同样只允许删除权限范围内的角色
+	if ok, err := canGrant(c, role.Permissions); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	} else if !ok {
+		c.JSON(http.StatusForbidden, util.Err(PermissionExceeded))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetRole, role.ID)
+	audit.SetBefore(c, role)
+
+	var userIDs []uint64
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserRole{}).
+			Where("role_id = ?", role.ID).
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("role_id = ?", role.ID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&role).Error; err != nil {
+			return err
+		}
+
+		return model.SyncAdminFlag(tx, userIDs...)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	model.InvalidateUserCache(c.Request.Context(), userIDs...)
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/audit"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
@@ -86,6 +87,9 @@ func CreateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetTarget(c, audit.TargetSystemConfig, config.Key)
+	audit.SetAfter(c, config)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -154,6 +158,9 @@ func UpdateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetTarget(c, audit.TargetSystemConfig, config.Key)
+	audit.SetBefore(c, config)
+
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
 		// 更新配置
 		if err := tx.Model(&config).
@@ -174,6 +181,8 @@ func UpdateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, config)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -197,6 +206,9 @@ func DeleteSystemConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetTarget(c, audit.TargetSystemConfig, config.Key)
+	audit.SetBefore(c, config)
+
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
 		// 删除配置
 		if err := tx.Delete(&config).Error; err != nil {
@@ -0,0 +1,38 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task_queue
+
+const (
+	QueueInfoObjKey = "admin_task_queue_info_obj"
+)
+
+const (
+	// HistoryDays 队列详情中展示的每日处理统计天数
+	HistoryDays = 7
+	// DefaultPageSize 任务列表默认分页大小
+	DefaultPageSize = 20
+)
+
+// 可查询的任务状态，asynq 中执行失败的任务处于 retry（等待重试）或 archived（重试耗尽）状态
+const (
+	TaskStatePending   = "pending"
+	TaskStateActive    = "active"
+	TaskStateScheduled = "scheduled"
+	TaskStateRetry     = "retry"
+	TaskStateArchived  = "archived"
+	TaskStateCompleted = "completed"
+)
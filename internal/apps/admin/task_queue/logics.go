@@ -0,0 +1,168 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task_queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/task"
+)
+
+var (
+	inspector     *asynq.Inspector
+	inspectorOnce sync.Once
+)
+
+// getInspector 懒加载 asynq Inspector，API 进程内共享一个连接
+func getInspector() *asynq.Inspector {
+	inspectorOnce.Do(func() {
+		inspector = asynq.NewInspector(task.RedisOpt)
+	})
+	return inspector
+}
+
+// QueueResponse 队列概况
+type QueueResponse struct {
+	Queue          string `json:"queue"`
+	Size           int    `json:"size"`
+	Pending        int    `json:"pending"`
+	Active         int    `json:"active"`
+	Scheduled      int    `json:"scheduled"`
+	Retry          int    `json:"retry"`
+	Archived       int    `json:"archived"`
+	Completed      int    `json:"completed"`
+	ProcessedToday int    `json:"processed_today"`
+	FailedToday    int    `json:"failed_today"`
+	LatencyMs      int64  `json:"latency_ms"`
+	MemoryUsage    int64  `json:"memory_usage"`
+	Paused         bool   `json:"paused"`
+}
+
+// DailyStatsResponse 队列每日处理统计
+type DailyStatsResponse struct {
+	Date      string `json:"date"`
+	Processed int    `json:"processed"`
+	Failed    int    `json:"failed"`
+}
+
+// TaskResponse 任务详情，payload 与 result 为合法 JSON 时原样返回，否则以字符串返回
+type TaskResponse struct {
+	ID            string      `json:"id"`
+	Queue         string      `json:"queue"`
+	Type          string      `json:"type"`
+	State         string      `json:"state"`
+	Payload       interface{} `json:"payload"`
+	MaxRetry      int         `json:"max_retry"`
+	Retried       int         `json:"retried"`
+	LastErr       string      `json:"last_err"`
+	LastFailedAt  *time.Time  `json:"last_failed_at"`
+	NextProcessAt *time.Time  `json:"next_process_at"`
+	CompletedAt   *time.Time  `json:"completed_at"`
+	TimeoutMs     int64       `json:"timeout_ms"`
+	Deadline      *time.Time  `json:"deadline"`
+	IsOrphaned    bool        `json:"is_orphaned"`
+	Result        interface{} `json:"result"`
+}
+
+func newQueueResponse(info *asynq.QueueInfo) QueueResponse {
+	return QueueResponse{
+		Queue:          info.Queue,
+		Size:           info.Size,
+		Pending:        info.Pending,
+		Active:         info.Active,
+		Scheduled:      info.Scheduled,
+		Retry:          info.Retry,
+		Archived:       info.Archived,
+		Completed:      info.Completed,
+		ProcessedToday: info.Processed,
+		FailedToday:    info.Failed,
+		LatencyMs:      info.Latency.Milliseconds(),
+		MemoryUsage:    info.MemoryUsage,
+		Paused:         info.Paused,
+	}
+}
+
+func newTaskResponse(info *asynq.TaskInfo) TaskResponse {
+	return TaskResponse{
+		ID:            info.ID,
+		Queue:         info.Queue,
+		Type:          info.Type,
+		State:         info.State.String(),
+		Payload:       decodeBytes(info.Payload),
+		MaxRetry:      info.MaxRetry,
+		Retried:       info.Retried,
+		LastErr:       info.LastErr,
+		LastFailedAt:  timeOrNil(info.LastFailedAt),
+		NextProcessAt: timeOrNil(info.NextProcessAt),
+		CompletedAt:   timeOrNil(info.CompletedAt),
+		TimeoutMs:     info.Timeout.Milliseconds(),
+		Deadline:      timeOrNil(info.Deadline),
+		IsOrphaned:    info.IsOrphaned,
+		Result:        decodeBytes(info.Result),
+	}
+}
+
+// decodeBytes 任务载荷优先按 JSON 展示
+func decodeBytes(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	return string(data)
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// listTasksByState 按状态分页查询任务，返回该状态下的任务总数
+func listTasksByState(info *asynq.QueueInfo, state string, page, pageSize int) ([]*asynq.TaskInfo, int, error) {
+	opts := []asynq.ListOption{asynq.Page(page), asynq.PageSize(pageSize)}
+	insp := getInspector()
+
+	switch state {
+	case TaskStatePending:
+		tasks, err := insp.ListPendingTasks(info.Queue, opts...)
+		return tasks, info.Pending, err
+	case TaskStateActive:
+		tasks, err := insp.ListActiveTasks(info.Queue, opts...)
+		return tasks, info.Active, err
+	case TaskStateScheduled:
+		tasks, err := insp.ListScheduledTasks(info.Queue, opts...)
+		return tasks, info.Scheduled, err
+	case TaskStateRetry:
+		tasks, err := insp.ListRetryTasks(info.Queue, opts...)
+		return tasks, info.Retry, err
+	case TaskStateArchived:
+		tasks, err := insp.ListArchivedTasks(info.Queue, opts...)
+		return tasks, info.Archived, err
+	case TaskStateCompleted:
+		tasks, err := insp.ListCompletedTasks(info.Queue, opts...)
+		return tasks, info.Completed, err
+	default:
+		return nil, 0, fmt.Errorf("unsupported task state %q", state)
+	}
+}
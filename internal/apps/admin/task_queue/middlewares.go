@@ -0,0 +1,45 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task_queue
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// RequireQueue 加载路径参数中的队列信息，需在 RequirePermission 之后使用，避免无权限时暴露队列是否存在
+func RequireQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info, err := getInspector().GetQueueInfo(c.Param("queue"))
+		if err != nil {
+			if errors.Is(err, asynq.ErrQueueNotFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, util.Err(QueueNotFound))
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+			return
+		}
+
+		util.SetToContext(c, QueueInfoObjKey, info)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task_queue
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// QueueDetailResponse 队列详情
+type QueueDetailResponse struct {
+	QueueResponse
+	History []DailyStatsResponse `json:"history"`
+}
+
+// ListTasksRequest 任务列表请求
+type ListTasksRequest struct {
+	State    string `form:"state" binding:"required,oneof=pending active scheduled retry archived completed"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ListTasksResponse 任务列表响应
+type ListTasksResponse struct {
+	util.PageResponse
+	Tasks []TaskResponse `json:"tasks"`
+}
+
+// BatchResultResponse 批量操作结果
+type BatchResultResponse struct {
+	Affected int `json:"affected"`
+}
+
+// TaskURIRequest 任务路径参数
+type TaskURIRequest struct {
+	TaskID string `uri:"taskId" binding:"required"`
+}
+
+// ListQueues 获取全部队列概况
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues [get]
+func ListQueues(c *gin.Context) {
+	insp := getInspector()
+
+	queues, err := insp.Queues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	items := make([]QueueResponse, 0, len(queues))
+	for _, queue := range queues {
+		info, errInfo := insp.GetQueueInfo(queue)
+		if errInfo != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(errInfo.Error()))
+			return
+		}
+		items = append(items, newQueueResponse(info))
+	}
+
+	c.JSON(http.StatusOK, util.OK(items))
+}
+
+// GetQueue 获取队列详情及近期每日处理统计
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue} [get]
+func GetQueue(c *gin.Context) {
+	info, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	history, err := getInspector().History(info.Queue, HistoryDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := QueueDetailResponse{
+		QueueResponse: newQueueResponse(info),
+		History:       make([]DailyStatsResponse, 0, len(history)),
+	}
+	for _, stats := range history {
+		response.History = append(response.History, DailyStatsResponse{
+			Date:      stats.Date.Format("2006-01-02"),
+			Processed: stats.Processed,
+			Failed:    stats.Failed,
+		})
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// PauseQueue 暂停队列，暂停期间 worker 不再从该队列取任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/pause [post]
+func PauseQueue(c *gin.Context) {
+	setQueuePaused(c, true)
+}
+
+// ResumeQueue 恢复已暂停的队列
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/resume [post]
+func ResumeQueue(c *gin.Context) {
+	setQueuePaused(c, false)
+}
+
+func setQueuePaused(c *gin.Context, paused bool) {
+	info, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	if info.Paused != paused {
+		var err error
+		if paused {
+			err = getInspector().PauseQueue(info.Queue)
+		} else {
+			err = getInspector().UnpauseQueue(info.Queue)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+	}
+
+	audit.SetTarget(c, audit.TargetTaskQueue, info.Queue)
+	audit.SetBefore(c, gin.H{"paused": info.Paused})
+	audit.SetAfter(c, gin.H{"paused": paused})
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListTasks 按状态分页获取队列中的任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Param request query ListTasksRequest true "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/tasks [get]
+func ListTasks(c *gin.Context) {
+	var req ListTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = DefaultPageSize
+	}
+
+	info, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	tasks, count, err := listTasksByState(info, req.State, req.Page, req.PageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	total := int64(count)
+	response := ListTasksResponse{
+		PageResponse: util.PageResponse{
+			Total:    &total,
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			HasMore:  int64(req.Page*req.PageSize) < total,
+		},
+		Tasks: make([]TaskResponse, 0, len(tasks)),
+	}
+	for _, t := range tasks {
+		response.Tasks = append(response.Tasks, newTaskResponse(t))
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetTask 获取任务详情
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Param taskId path string true "任务 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/tasks/{taskId} [get]
+func GetTask(c *gin.Context) {
+	info, ok := loadTask(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(newTaskResponse(info)))
+}
+
+// RunTask 立即执行处于 scheduled、retry 或 archived 状态的任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Param taskId path string true "任务 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/tasks/{taskId}/run [post]
+func RunTask(c *gin.Context) {
+	info, ok := loadTask(c)
+	if !ok {
+		return
+	}
+
+	if err := getInspector().RunTask(info.Queue, info.ID); err != nil {
+		if errors.Is(err, asynq.ErrTaskNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(TaskNotFound))
+		} else {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetTask, info.ID)
+	audit.SetBefore(c, newTaskResponse(info))
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// DeleteTask 删除非执行中的任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Param taskId path string true "任务 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/tasks/{taskId} [delete]
+func DeleteTask(c *gin.Context) {
+	info, ok := loadTask(c)
+	if !ok {
+		return
+	}
+
+	if err := getInspector().DeleteTask(info.Queue, info.ID); err != nil {
+		if errors.Is(err, asynq.ErrTaskNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(TaskNotFound))
+		} else {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetTask, info.ID)
+	audit.SetBefore(c, newTaskResponse(info))
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// RunAllArchivedTasks 重新执行队列中全部已归档任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/archived/run [post]
+func RunAllArchivedTasks(c *gin.Context) {
+	info, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	affected, err := getInspector().RunAllArchivedTasks(info.Queue)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetTaskQueue, info.Queue)
+	audit.SetAfter(c, gin.H{"action": "run_archived", "affected": affected})
+
+	c.JSON(http.StatusOK, util.OK(BatchResultResponse{Affected: affected}))
+}
+
+// DeleteAllArchivedTasks 删除队列中全部已归档任务
+// @Tags admin
+// @Produce json
+// @Param queue path string true "队列名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/tasks/queues/{queue}/archived [delete]
+func DeleteAllArchivedTasks(c *gin.Context) {
+	info, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	affected, err := getInspector().DeleteAllArchivedTasks(info.Queue)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetTaskQueue, info.Queue)
+	audit.SetAfter(c, gin.H{"action": "delete_archived", "affected": affected})
+
+	c.JSON(http.StatusOK, util.OK(BatchResultResponse{Affected: affected}))
+}
+
+// loadTask 加载路径参数中的任务，失败时直接写入响应
+func loadTask(c *gin.Context) (*asynq.TaskInfo, bool) {
+	var req TaskURIRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return nil, false
+	}
+
+	queue, _ := util.GetFromContext[*asynq.QueueInfo](c, QueueInfoObjKey)
+
+	info, err := getInspector().GetTaskInfo(queue.Queue, req.TaskID)
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(TaskNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return nil, false
+	}
+	return info, true
+}
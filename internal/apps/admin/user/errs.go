@@ -0,0 +1,28 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+const (
+	UserNotFound      = "用户不存在"
+	CannotOperateSelf = "不能对自己执行该操作"
+	UserAlreadyBanned = "用户已被封禁"
+	UserNotBanned     = "用户未被封禁"
+	BanReasonRequired = "封禁理由不能为空"
+	InvalidUserID     = "用户ID格式错误"
+	RoleNotFound      = "角色不存在"
+	RoleExceeded      = "不能授予或撤销超出自身权限的角色"
+)
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+)
+
+// Ban 封禁用户并清除其全部登录会话
+func Ban(ctx context.Context, user *model.User, reason string) error {
+	if reason == "" {
+		return errors.New(BanReasonRequired)
+	}
+
+	now := time.Now()
+	result := db.DB(ctx).Model(&model.User{}).
+		Where("id = ? AND is_active = ?", user.ID, true).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"ban_reason": reason,
+			"banned_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(UserAlreadyBanned)
+	}
+
+	user.IsActive = false
+	user.BanReason = reason
+	user.BannedAt = &now
+	model.InvalidateUserCache(ctx, user.ID)
+
+	return oauth.InvalidateUserSessions(ctx, user.ID)
+}
+
+// Unban 解除用户封禁
+func Unban(ctx context.Context, user *model.User) error {
+	result := db.DB(ctx).Model(&model.User{}).
+		Where("id = ? AND is_active = ?", user.ID, false).
+		Updates(map[string]interface{}{
+			"is_active":  true,
+			"ban_reason": "",
+			"banned_at":  nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(UserNotBanned)
+	}
+
+	user.IsActive = true
+	user.BanReason = ""
+	user.BannedAt = nil
+	model.InvalidateUserCache(ctx, user.ID)
+	return nil
+}
+
+// AssignRoles 替换用户拥有的角色，并同步 IsAdmin 标记
+func AssignRoles(ctx context.Context, user *model.User, roleIDs []uint64, grantedBy uint64) ([]model.Role, error) {
+	// 去重后再与查询结果比对，重复的角色 ID 不应被视为角色不存在
+	roleIDs = slices.Compact(slices.Sorted(slices.Values(roleIDs)))
+
+	var roles []model.Role
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(roleIDs) > 0 {
+			if err := tx.Where("id IN ?", roleIDs).Order("id ASC").Find(&roles).Error; err != nil {
+				return err
+			}
+			if len(roles) != len(roleIDs) {
+				return errors.New(RoleNotFound)
+			}
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if len(roles) > 0 {
+			userRoles := make([]model.UserRole, 0, len(roles))
+			for _, role := range roles {
+				userRoles = append(userRoles, model.UserRole{UserID: user.ID, RoleID: role.ID, GrantedBy: grantedBy})
+			}
+			if err := tx.Create(&userRoles).Error; err != nil {
+				return err
+			}
+		}
+
+		return model.SyncAdminFlag(tx, user.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	user.IsAdmin = len(roles) > 0
+	model.InvalidateUserCache(ctx, user.ID)
+	return roles, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// RequireUser 加载路径参数中的目标用户，需在 RequirePermission 之后使用，避免无权限时暴露用户是否存在
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, util.Err(InvalidUserID))
+			return
+		}
+
+		var user model.User
+		if err := user.GetByID(db.DB(c.Request.Context()), userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, util.Err(UserNotFound))
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+			return
+		}
+
+		util.SetToContext(c, TargetUserObjKey, &user)
+
+		c.Next()
+	}
+}
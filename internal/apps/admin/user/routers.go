@@ -0,0 +1,395 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// UserSummary 管理后台展示的用户信息（不含支付密码与签名密钥）
+type UserSummary struct {
+	ID               uint64           `json:"id"`
+	Username         string           `json:"username"`
+	Nickname         string           `json:"nickname"`
+	AvatarUrl        string           `json:"avatar_url"`
+	TrustLevel       model.TrustLevel `json:"trust_level"`
+	PayScore         int64            `json:"pay_score"`
+	AvailableBalance decimal.Decimal  `json:"available_balance"`
+	CommunityBalance decimal.Decimal  `json:"community_balance"`
+	TotalReceive     decimal.Decimal  `json:"total_receive"`
+	TotalPayment     decimal.Decimal  `json:"total_payment"`
+	TotalTransfer    decimal.Decimal  `json:"total_transfer"`
+	TotalCommunity   decimal.Decimal  `json:"total_community"`
+	IsActive         bool             `json:"is_active"`
+	IsAdmin          bool             `json:"is_admin"`
+	IsPayKey         bool             `json:"is_pay_key"`
+	BanReason        string           `json:"ban_reason"`
+	BannedAt         *time.Time       `json:"banned_at"`
+	LastLoginAt      time.Time        `json:"last_login_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// APIKeySummary 管理后台展示的 API Key 信息（不含 ClientSecret）
+type APIKeySummary struct {
+	ID             uint64         `json:"id"`
+	ClientID       string         `json:"client_id"`
+	AppName        string         `json:"app_name"`
+	AppHomepageURL string         `json:"app_homepage_url"`
+	NotifyURL      string         `json:"notify_url"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at"`
+}
+
+// newUserSummary 转换为管理后台用户信息
+func newUserSummary(u *model.User) UserSummary {
+	return UserSummary{
+		ID:               u.ID,
+		Username:         u.Username,
+		Nickname:         u.Nickname,
+		AvatarUrl:        u.AvatarUrl,
+		TrustLevel:       u.TrustLevel,
+		PayScore:         u.PayScore,
+		AvailableBalance: u.AvailableBalance,
+		CommunityBalance: u.CommunityBalance,
+		TotalReceive:     u.TotalReceive,
+		TotalPayment:     u.TotalPayment,
+		TotalTransfer:    u.TotalTransfer,
+		TotalCommunity:   u.TotalCommunity,
+		IsActive:         u.IsActive,
+		IsAdmin:          u.IsAdmin,
+		IsPayKey:         u.PayKey != "",
+		BanReason:        u.BanReason,
+		BannedAt:         u.BannedAt,
+		LastLoginAt:      u.LastLoginAt,
+		CreatedAt:        u.CreatedAt,
+	}
+}
+
+// ListUsersRequest 查询用户列表请求
+type ListUsersRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Keyword  string `json:"keyword" form:"keyword" binding:"max=64"`
+	IsActive *bool  `json:"is_active" form:"is_active"`
+	IsAdmin  *bool  `json:"is_admin" form:"is_admin"`
+}
+
+// ListUsersResponse 查询用户列表响应
+type ListUsersResponse struct {
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Users    []UserSummary `json:"users"`
+}
+
+// ListUsers 搜索用户
+// @Tags admin
+// @Produce json
+// @Param request query ListUsersRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users [get]
+func ListUsers(c *gin.Context) {
+	var req ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.User{})
+
+	if req.Keyword != "" {
+		pattern := util.ContainsPattern(req.Keyword)
+		if userID, err := strconv.ParseUint(req.Keyword, 10, 64); err == nil {
+			baseQuery = baseQuery.Where("id = ? OR username ILIKE ? OR nickname ILIKE ?", userID, pattern, pattern)
+		} else {
+			baseQuery = baseQuery.Where("username ILIKE ? OR nickname ILIKE ?", pattern, pattern)
+		}
+	}
+	if req.IsActive != nil {
+		baseQuery = baseQuery.Where("is_active = ?", *req.IsActive)
+	}
+	if req.IsAdmin != nil {
+		baseQuery = baseQuery.Where("is_admin = ?", *req.IsAdmin)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var users []model.User
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Order("id ASC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListUsersResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Users:    make([]UserSummary, 0, len(users)),
+	}
+	for i := range users {
+		response.Users = append(response.Users, newUserSummary(&users[i]))
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// UserDetailResponse 用户详情响应
+type UserDetailResponse struct {
+	User         UserSummary          `json:"user"`
+	Roles        []model.Role         `json:"roles"`
+	PayConfig    *model.UserPayConfig `json:"pay_config"`
+	APIKeys      []APIKeySummary      `json:"api_keys"`
+	RecentOrders []model.Order        `json:"recent_orders"`
+}
+
+// GetUser 获取用户详情
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "用户ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id} [get]
+func GetUser(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, TargetUserObjKey)
+	ctx := c.Request.Context()
+
+	response := &UserDetailResponse{
+		User:         newUserSummary(user),
+		APIKeys:      []APIKeySummary{},
+		RecentOrders: []model.Order{},
+	}
+
+	roles, err := model.GetRolesByUserID(db.DB(ctx), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	response.Roles = roles
+
+	var payConfig model.UserPayConfig
+	if err := payConfig.GetByPayScore(db.DB(ctx), user.PayScore); err == nil {
+		response.PayConfig = &payConfig
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	// 包含已删除的 API Key，便于追溯历史订单来源
+	if err := db.DB(ctx).Unscoped().Model(&model.MerchantAPIKey{}).
+		Where("user_id = ?", user.ID).
+		Order("created_at DESC").
+		Find(&response.APIKeys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(ctx).Model(&model.Order{}).
+		Select("orders.*, payer_user.username as payer_username, payee_user.username as payee_username").
+		Joins("LEFT JOIN users as payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN users as payee_user ON orders.payee_user_id = payee_user.id").
+		Where("orders.payer_user_id = ? OR orders.payee_user_id = ?", user.ID, user.ID).
+		Order("orders.created_at DESC").
+		Limit(RecentOrdersLimit).
+		Find(&response.RecentOrders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// BanUserRequest 封禁用户请求
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// BanUser 封禁用户
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户ID"
+// @Param request body BanUserRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/ban [post]
+func BanUser(c *gin.Context) {
+	var req BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	user, _ := util.GetFromContext[*model.User](c, TargetUserObjKey)
+	if currentUser.ID == user.ID {
+		c.JSON(http.StatusBadRequest, util.Err(CannotOperateSelf))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetUser, user.ID)
+	audit.SetBefore(c, newUserSummary(user))
+
+	if err := Ban(c.Request.Context(), user, req.Reason); err != nil {
+		switch err.Error() {
+		case BanReasonRequired, UserAlreadyBanned:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetAfter(c, newUserSummary(user))
+
+	c.JSON(http.StatusOK, util.OK(newUserSummary(user)))
+}
+
+// UnbanUser 解除用户封禁
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "用户ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/unban [post]
+func UnbanUser(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, TargetUserObjKey)
+
+	audit.SetTarget(c, audit.TargetUser, user.ID)
+	audit.SetBefore(c, newUserSummary(user))
+
+	if err := Unban(c.Request.Context(), user); err != nil {
+		if err.Error() == UserNotBanned {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetAfter(c, newUserSummary(user))
+
+	c.JSON(http.StatusOK, util.OK(newUserSummary(user)))
+}
+
+// ListUserRoles 获取用户角色
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "用户ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/roles [get]
+func ListUserRoles(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, TargetUserObjKey)
+
+	roles, err := model.GetRolesByUserID(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(roles))
+}
+
+// UpdateUserRolesRequest 设置用户角色请求
+type UpdateUserRolesRequest struct {
+	RoleIDs []uint64 `json:"role_ids" binding:"omitempty,dive,min=1"`
+}
+
+// UpdateUserRoles 设置用户角色，角色为空时取消管理员身份
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户ID"
+// @Param request body UpdateUserRolesRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/roles [put]
+func UpdateUserRoles(c *gin.Context) {
+	var req UpdateUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	user, _ := util.GetFromContext[*model.User](c, TargetUserObjKey)
+	if currentUser.ID == user.ID {
+		c.JSON(http.StatusBadRequest, util.Err(CannotOperateSelf))
+		return
+	}
+
+	ctx := c.Request.Context()
+	before, err := model.GetRolesByUserID(db.DB(ctx), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var requested []model.Role
+	if len(req.RoleIDs) > 0 {
+		if err := db.DB(ctx).Where("id IN ?", req.RoleIDs).Find(&requested).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+	}
+
+	// 撤销与授予的角色都需在当前管理员的权限范围内，超级管理员角色只能由超级管理员分配
+	granted, err := model.GetPermissionsByUserID(db.DB(db.WithPrimary(ctx)), currentUser.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	for _, role := range slices.Concat(before, requested) {
+		if !model.CoversPermissions(granted, role.Permissions) {
+			c.JSON(http.StatusForbidden, util.Err(RoleExceeded))
+			return
+		}
+	}
+
+	audit.SetTarget(c, audit.TargetUser, user.ID)
+	audit.SetBefore(c, gin.H{"roles": before})
+
+	roles, err := AssignRoles(ctx, user, req.RoleIDs, currentUser.ID)
+	if err != nil {
+		if err.Error() == RoleNotFound {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	audit.SetAfter(c, gin.H{"roles": roles})
+
+	c.JSON(http.StatusOK, util.OK(roles))
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/audit"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
@@ -90,6 +91,10 @@ func CreateUserPayConfig(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	model.InvalidatePayTierCache(c.Request.Context())
+
+	audit.SetTarget(c, audit.TargetUserPayConfig, config.ID)
+	audit.SetAfter(c, config)
 
 	c.JSON(http.StatusOK, util.OK(config))
 }
@@ -163,6 +168,9 @@ func UpdateUserPayConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetTarget(c, audit.TargetUserPayConfig, config.ID)
+	audit.SetBefore(c, config)
+
 	// 更新配置
 	if err := db.DB(c.Request.Context()).
 		Model(&config).
@@ -176,6 +184,9 @@ func UpdateUserPayConfig(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	model.InvalidatePayTierCache(c.Request.Context())
+
+	audit.SetAfter(c, config)
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
@@ -198,10 +209,14 @@ func DeleteUserPayConfig(c *gin.Context) {
 		return
 	}
 
+	audit.SetTarget(c, audit.TargetUserPayConfig, config.ID)
+	audit.SetBefore(c, config)
+
 	if err := db.DB(c.Request.Context()).Delete(&config).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	model.InvalidatePayTierCache(c.Request.Context())
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import "github.com/linux-do/pay/internal/model"
+
+const (
+	// DateLayout 统计日期格式
+	DateLayout = "2006-01-02"
+	// DefaultRangeDays 未指定日期范围时默认查询的天数
+	DefaultRangeDays = 30
+	// MaxRangeDays 单次查询允许的最大天数
+	MaxRangeDays = 366
+)
+
+// periodDays 商户统计可选的快捷周期
+var periodDays = map[string]int{
+	"7d":   7,
+	"30d":  30,
+	"90d":  90,
+	"365d": 365,
+}
+
+// MerchantTopLinksLimit 商户统计中返回的热门支付链接数量
+const MerchantTopLinksLimit = 10
+
+// settledStatuses 已成交的订单状态，争议中、已退款、已拒绝退款的订单均视为已成交
+var settledStatuses = []model.OrderStatus{
+	model.OrderStatusSuccess,
+	model.OrderStatusDisputing,
+	model.OrderStatusRefund,
+	model.OrderStatusRefused,
+}
+
+// platformOrderTypes 计入平台交易额的订单类型
+var platformOrderTypes = []model.OrderType{
+	model.OrderTypePayment,
+	model.OrderTypeOnline,
+	model.OrderTypeTransfer,
+}
+
+// merchantOrderTypes 商户收款订单类型，用于计算争议率
+var merchantOrderTypes = []model.OrderType{
+	model.OrderTypePayment,
+	model.OrderTypeOnline,
+}
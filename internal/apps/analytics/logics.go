@@ -0,0 +1,116 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linux-do/pay/internal/task/schedule"
+)
+
+// statsLocation 统计日期所在时区，与定时任务调度时区一致，保证日切与汇总任务的触发时间对齐
+var statsLocation = loadStatsLocation()
+
+// loadStatsLocation 时区无效时调度器无法启动，此处退回 UTC 以保证 SQL 中的时区名称有效
+func loadStatsLocation() *time.Location {
+	location, err := schedule.Location()
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// DateRange 统计日期范围（闭区间）
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseDateRange 解析日期范围，未指定时默认最近 DefaultRangeDays 天
+func ParseDateRange(startDate, endDate string) (DateRange, error) {
+	today := truncateDay(time.Now())
+	r := DateRange{Start: today.AddDate(0, 0, -(DefaultRangeDays - 1)), End: today}
+
+	if endDate != "" {
+		end, err := time.ParseInLocation(DateLayout, endDate, statsLocation)
+		if err != nil {
+			return r, err
+		}
+		r.End = end
+		if startDate == "" {
+			r.Start = end.AddDate(0, 0, -(DefaultRangeDays - 1))
+		}
+	}
+	if startDate != "" {
+		start, err := time.ParseInLocation(DateLayout, startDate, statsLocation)
+		if err != nil {
+			return r, err
+		}
+		r.Start = start
+	}
+
+	if r.Start.After(r.End) {
+		return r, errors.New(InvalidDateRange)
+	}
+	if r.Days() > MaxRangeDays {
+		return r, fmt.Errorf(DateRangeTooLarge, MaxRangeDays)
+	}
+	return r, nil
+}
+
+// PeriodRange 根据快捷周期生成截至今日的日期范围
+func PeriodRange(period string) (DateRange, bool) {
+	days, ok := periodDays[period]
+	if !ok {
+		return DateRange{}, false
+	}
+	today := truncateDay(time.Now())
+	return DateRange{Start: today.AddDate(0, 0, -(days - 1)), End: today}, true
+}
+
+// Days 范围内的天数
+func (r DateRange) Days() int {
+	return int(r.End.Sub(r.Start).Hours()/24) + 1
+}
+
+// StartTime 范围起始时间
+func (r DateRange) StartTime() time.Time {
+	return r.Start
+}
+
+// EndTime 范围结束时间（不含）
+func (r DateRange) EndTime() time.Time {
+	return r.End.AddDate(0, 0, 1)
+}
+
+// StartDate 范围起始日期字符串
+func (r DateRange) StartDate() string {
+	return r.Start.Format(DateLayout)
+}
+
+// EndDate 范围结束日期字符串
+func (r DateRange) EndDate() string {
+	return r.End.Format(DateLayout)
+}
+
+// truncateDay 截断到统计时区的零点
+func truncateDay(t time.Time) time.Time {
+	t = t.In(statsLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, statsLocation)
+}
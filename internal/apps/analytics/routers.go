@@ -0,0 +1,391 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// DateRangeRequest 日期范围查询参数
+type DateRangeRequest struct {
+	StartDate string `json:"start_date" form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// bindDateRange 绑定并解析日期范围
+func bindDateRange(c *gin.Context, req *DateRangeRequest) (DateRange, bool) {
+	if err := c.ShouldBindQuery(req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return DateRange{}, false
+	}
+
+	r, err := ParseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return DateRange{}, false
+	}
+	return r, true
+}
+
+// OverviewResponse 平台概览
+type OverviewResponse struct {
+	StartDate      string          `json:"start_date"`
+	EndDate        string          `json:"end_date"`
+	SettledCount   int64           `json:"settled_count"`
+	SettledAmount  decimal.Decimal `json:"settled_amount"`
+	Fee            decimal.Decimal `json:"fee"`
+	PaymentAmount  decimal.Decimal `json:"payment_amount"`
+	TransferCount  int64           `json:"transfer_count"`
+	TransferAmount decimal.Decimal `json:"transfer_amount"`
+	RefundAmount   decimal.Decimal `json:"refund_amount"`
+	NewUsers       int64           `json:"new_users"`
+	NewMerchants   int64           `json:"new_merchants"`
+	PeakPayers     int64           `json:"peak_active_payers"`
+	AvgPayers      decimal.Decimal `json:"avg_active_payers"`
+	DisputeCount   int64           `json:"dispute_count"`
+	DisputeRefund  int64           `json:"dispute_refund"`
+	DisputeRate    decimal.Decimal `json:"dispute_rate"`
+}
+
+// GetOverview 获取平台统计概览
+// @Tags admin
+// @Produce json
+// @Param request query DateRangeRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/stats/overview [get]
+func GetOverview(c *gin.Context) {
+	var req DateRangeRequest
+	r, ok := bindDateRange(c, &req)
+	if !ok {
+		return
+	}
+
+	readDB := db.DB(db.WithReplica(c.Request.Context()))
+
+	response := &OverviewResponse{StartDate: r.StartDate(), EndDate: r.EndDate()}
+	if err := readDB.Model(&model.AnalyticsDailyPlatformStat{}).
+		Select(`COALESCE(SUM(settled_count), 0) AS settled_count,
+			COALESCE(SUM(settled_amount), 0) AS settled_amount,
+			COALESCE(SUM(fee), 0) AS fee,
+			COALESCE(SUM(new_users), 0) AS new_users,
+			COALESCE(SUM(new_merchants), 0) AS new_merchants,
+			COALESCE(MAX(active_payers), 0) AS peak_payers,
+			COALESCE(ROUND(AVG(active_payers), 2), 0) AS avg_payers,
+			COALESCE(SUM(dispute_count), 0) AS dispute_count,
+			COALESCE(SUM(dispute_refund), 0) AS dispute_refund`).
+		Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+		Scan(response).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var typeTotals []TypeTotal
+	if err := queryTypeTotals(c, r, "").Scan(&typeTotals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var merchantSettled int64
+	for _, t := range typeTotals {
+		switch t.Type {
+		case model.OrderTypePayment, model.OrderTypeOnline:
+			merchantSettled += t.SettledCount
+			response.PaymentAmount = response.PaymentAmount.Add(t.SettledAmount)
+			response.RefundAmount = response.RefundAmount.Add(t.RefundAmount)
+		case model.OrderTypeTransfer:
+			response.TransferCount = t.SettledCount
+			response.TransferAmount = t.SettledAmount
+		}
+	}
+	if merchantSettled > 0 {
+		response.DisputeRate = decimal.NewFromInt(response.DisputeCount).Div(decimal.NewFromInt(merchantSettled)).Round(4)
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ListDailyStats 获取平台每日统计
+// @Tags admin
+// @Produce json
+// @Param request query DateRangeRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/stats/daily [get]
+func ListDailyStats(c *gin.Context) {
+	var req DateRangeRequest
+	r, ok := bindDateRange(c, &req)
+	if !ok {
+		return
+	}
+
+	var stats []model.AnalyticsDailyPlatformStat
+	if err := db.DB(db.WithReplica(c.Request.Context())).
+		Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+		Order("date ASC").
+		Find(&stats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(stats))
+}
+
+// TypeStatsRequest 按订单类型统计请求
+type TypeStatsRequest struct {
+	DateRangeRequest
+	Type string `json:"type" form:"type" binding:"omitempty,oneof=payment transfer community online adjustment"`
+}
+
+// TypeTotal 单个订单类型在日期范围内的合计
+type TypeTotal struct {
+	Type          model.OrderType `json:"type"`
+	OrderCount    int64           `json:"order_count"`
+	SettledCount  int64           `json:"settled_count"`
+	SettledAmount decimal.Decimal `json:"settled_amount"`
+	Fee           decimal.Decimal `json:"fee"`
+	RefundCount   int64           `json:"refund_count"`
+	RefundAmount  decimal.Decimal `json:"refund_amount"`
+}
+
+// TypeStatsResponse 按订单类型统计响应
+type TypeStatsResponse struct {
+	Totals []TypeTotal                     `json:"totals"`
+	Daily  []model.AnalyticsDailyOrderStat `json:"daily"`
+}
+
+// queryTypeTotals 构建按类型合计的查询
+func queryTypeTotals(c *gin.Context, r DateRange, orderType string) *gorm.DB {
+	query := db.DB(db.WithReplica(c.Request.Context())).Model(&model.AnalyticsDailyOrderStat{}).
+		Select(`type,
+			SUM(order_count) AS order_count,
+			SUM(settled_count) AS settled_count,
+			SUM(settled_amount) AS settled_amount,
+			SUM(fee) AS fee,
+			SUM(refund_count) AS refund_count,
+			SUM(refund_amount) AS refund_amount`).
+		Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate())
+	if orderType != "" {
+		query = query.Where("type = ?", orderType)
+	}
+	return query.Group("type").Order("type ASC")
+}
+
+// ListTypeStats 获取按订单类型的统计
+// @Tags admin
+// @Produce json
+// @Param request query TypeStatsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/stats/types [get]
+func ListTypeStats(c *gin.Context) {
+	var req TypeStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	r, err := ParseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	response := &TypeStatsResponse{}
+	if err := queryTypeTotals(c, r, req.Type).Scan(&response.Totals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	dailyQuery := db.DB(db.WithReplica(c.Request.Context())).
+		Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate())
+	if req.Type != "" {
+		dailyQuery = dailyQuery.Where("type = ?", req.Type)
+	}
+	if err := dailyQuery.Order("date ASC").Order("type ASC").Find(&response.Daily).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// RebuildStatsRequest 重新汇总统计请求
+type RebuildStatsRequest struct {
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+}
+
+// RebuildStats 下发指定日期范围的统计重算任务
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body RebuildStatsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/stats/rebuild [post]
+func RebuildStats(c *gin.Context) {
+	var req RebuildStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	r, err := ParseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	payload, _ := json.Marshal(RollupPayload{StartDate: r.StartDate(), EndDate: r.EndDate()})
+	if _, err := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.AnalyticsRollupTask, payload),
+		asynq.MaxRetry(3),
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetAnalytics, r.StartDate()+"~"+r.EndDate())
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// MerchantStatsRequest 商户应用统计请求，指定 period 时忽略日期范围
+type MerchantStatsRequest struct {
+	DateRangeRequest
+	Period string `json:"period" form:"period" binding:"omitempty,oneof=7d 30d 90d 365d"`
+}
+
+// MerchantLinkStat 支付链接成交统计
+type MerchantLinkStat struct {
+	PaymentLinkID uint64          `json:"payment_link_id"`
+	ProductName   string          `json:"product_name"`
+	SettledCount  int64           `json:"settled_count"`
+	SettledAmount decimal.Decimal `json:"settled_amount"`
+}
+
+// MerchantStatsResponse 商户应用统计响应
+type MerchantStatsResponse struct {
+	StartDate      string                             `json:"start_date"`
+	EndDate        string                             `json:"end_date"`
+	Revenue        decimal.Decimal                    `json:"revenue"`
+	Fee            decimal.Decimal                    `json:"fee"`
+	NetRevenue     decimal.Decimal                    `json:"net_revenue"`
+	OrderCount     int64                              `json:"order_count"`
+	PendingCount   int64                              `json:"pending_count"`
+	ExpiredCount   int64                              `json:"expired_count"`
+	FailedCount    int64                              `json:"failed_count"`
+	SettledCount   int64                              `json:"settled_count"`
+	ConversionRate decimal.Decimal                    `json:"conversion_rate"`
+	RefundCount    int64                              `json:"refund_count"`
+	RefundAmount   decimal.Decimal                    `json:"refund_amount"`
+	DisputeCount   int64                              `json:"dispute_count"`
+	AvgTicket      decimal.Decimal                    `json:"avg_ticket"`
+	TopLinks       []MerchantLinkStat                 `json:"top_links"`
+	Daily          []model.AnalyticsDailyMerchantStat `json:"daily"`
+}
+
+// GetMerchantAppStats 获取商户应用的收款统计
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request query MerchantStatsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/stats [get]
+func GetMerchantAppStats(c *gin.Context) {
+	var req MerchantStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	r, ok := PeriodRange(req.Period)
+	if !ok {
+		var err error
+		if r, err = ParseDateRange(req.StartDate, req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+	readDB := db.DB(db.WithReplica(c.Request.Context()))
+
+	response := &MerchantStatsResponse{StartDate: r.StartDate(), EndDate: r.EndDate()}
+	if err := readDB.Model(&model.AnalyticsDailyMerchantStat{}).
+		Select(`COALESCE(SUM(settled_amount), 0) AS revenue,
+			COALESCE(SUM(fee), 0) AS fee,
+			COALESCE(SUM(order_count), 0) AS order_count,
+			COALESCE(SUM(pending_count), 0) AS pending_count,
+			COALESCE(SUM(expired_count), 0) AS expired_count,
+			COALESCE(SUM(failed_count), 0) AS failed_count,
+			COALESCE(SUM(settled_count), 0) AS settled_count,
+			COALESCE(SUM(refund_count), 0) AS refund_count,
+			COALESCE(SUM(refund_amount), 0) AS refund_amount,
+			COALESCE(SUM(dispute_count), 0) AS dispute_count`).
+		Where("client_id = ? AND date BETWEEN ? AND ?", apiKey.ClientID, r.StartDate(), r.EndDate()).
+		Scan(response).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response.NetRevenue = response.Revenue.Sub(response.Fee).Sub(response.RefundAmount)
+	if response.OrderCount > 0 {
+		response.ConversionRate = decimal.NewFromInt(response.SettledCount).Div(decimal.NewFromInt(response.OrderCount)).Round(4)
+	}
+	if response.SettledCount > 0 {
+		response.AvgTicket = response.Revenue.Div(decimal.NewFromInt(response.SettledCount)).Round(2)
+	}
+
+	// 已删除的支付链接仍保留历史成交记录
+	if err := readDB.Model(&model.AnalyticsDailyPaymentLinkStat{}).
+		Select(`analytics_daily_payment_link_stats.payment_link_id,
+			MAX(merchant_payment_links.product_name) AS product_name,
+			SUM(analytics_daily_payment_link_stats.settled_count) AS settled_count,
+			SUM(analytics_daily_payment_link_stats.settled_amount) AS settled_amount`).
+		Joins("LEFT JOIN merchant_payment_links ON merchant_payment_links.id = analytics_daily_payment_link_stats.payment_link_id").
+		Where("analytics_daily_payment_link_stats.client_id = ? AND analytics_daily_payment_link_stats.date BETWEEN ? AND ?",
+			apiKey.ClientID, r.StartDate(), r.EndDate()).
+		Group("analytics_daily_payment_link_stats.payment_link_id").
+		Order("settled_amount DESC").
+		Order("analytics_daily_payment_link_stats.payment_link_id ASC").
+		Limit(MerchantTopLinksLimit).
+		Scan(&response.TopLinks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := readDB.
+		Where("client_id = ? AND date BETWEEN ? AND ?", apiKey.ClientID, r.StartDate(), r.EndDate()).
+		Order("date ASC").
+		Find(&response.Daily).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
@@ -0,0 +1,389 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RollupPayload 统计汇总任务参数，为空时自动增量计算
+type RollupPayload struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// platformOrderStat 平台交易统计
+type platformOrderStat struct {
+	Date          time.Time
+	SettledCount  int64
+	SettledAmount decimal.Decimal
+	Fee           decimal.Decimal
+	ActivePayers  int64
+}
+
+// dailyCount 按日计数
+type dailyCount struct {
+	Date  time.Time
+	Count int64
+}
+
+// disputeDailyStat 按日争议统计
+type disputeDailyStat struct {
+	Date          time.Time
+	DisputeCount  int64
+	DisputeRefund int64
+}
+
+// HandleAnalyticsRollup 汇总订单与争议数据到日统计表
+func HandleAnalyticsRollup(ctx context.Context, t *asynq.Task) error {
+	var payload RollupPayload
+	if len(t.Payload()) > 0 {
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			return fmt.Errorf("解析任务参数失败: %w", err)
+		}
+	}
+
+	var (
+		r   DateRange
+		err error
+	)
+	if payload.StartDate != "" || payload.EndDate != "" {
+		r, err = ParseDateRange(payload.StartDate, payload.EndDate)
+	} else {
+		r, err = resolveIncrementalRange(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := Rollup(ctx, r); err != nil {
+		return err
+	}
+
+	logger.InfoF(ctx, "统计数据已汇总: %s ~ %s", r.StartDate(), r.EndDate())
+	return nil
+}
+
+// resolveIncrementalRange 计算本次增量汇总的日期范围
+// 已有统计时重算最近 AnalyticsRecomputeDays 天以覆盖退款、争议等状态变化；
+// 尚无统计或统计落后时从断点开始补数，每次最多 AnalyticsBackfillBatchDays 天
+func resolveIncrementalRange(ctx context.Context) (DateRange, error) {
+	today := truncateDay(time.Now())
+	recomputeDays := max(config.Config.Schedule.AnalyticsRecomputeDays, 1)
+	batchDays := max(config.Config.Schedule.AnalyticsBackfillBatchDays, recomputeDays+1)
+
+	var lastDate sql.NullTime
+	if err := db.DB(ctx).Model(&model.AnalyticsDailyPlatformStat{}).
+		Select("MAX(date)").
+		Scan(&lastDate).Error; err != nil {
+		return DateRange{}, fmt.Errorf("查询统计进度失败: %w", err)
+	}
+
+	var start time.Time
+	if lastDate.Valid {
+		last := time.Date(lastDate.Time.Year(), lastDate.Time.Month(), lastDate.Time.Day(), 0, 0, 0, 0, statsLocation)
+		start = last.AddDate(0, 0, -(recomputeDays - 1))
+	} else {
+		var firstOrderAt sql.NullTime
+		if err := db.DB(ctx).Model(&model.Order{}).
+			Select("MIN(created_at)").
+			Scan(&firstOrderAt).Error; err != nil {
+			return DateRange{}, fmt.Errorf("查询最早订单失败: %w", err)
+		}
+		start = today
+		if firstOrderAt.Valid {
+			start = truncateDay(firstOrderAt.Time)
+		}
+	}
+
+	if start.After(today) {
+		start = today
+	}
+	end := start.AddDate(0, 0, batchDays-1)
+	if end.After(today) {
+		end = today
+	}
+	return DateRange{Start: start, End: end}, nil
+}
+
+// Rollup 重新计算指定日期范围内的日统计
+func Rollup(ctx context.Context, r DateRange) error {
+	orderStats, err := aggregateOrderStats(ctx, r)
+	if err != nil {
+		return err
+	}
+	platformStats, err := aggregatePlatformStats(ctx, r)
+	if err != nil {
+		return err
+	}
+	merchantStats, err := aggregateMerchantStats(ctx, r)
+	if err != nil {
+		return err
+	}
+	linkStats, err := aggregatePaymentLinkStats(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+			Delete(&model.AnalyticsDailyOrderStat{}).Error; err != nil {
+			return fmt.Errorf("清理订单日统计失败: %w", err)
+		}
+		if len(orderStats) > 0 {
+			if err := tx.CreateInBatches(orderStats, 500).Error; err != nil {
+				return fmt.Errorf("写入订单日统计失败: %w", err)
+			}
+		}
+
+		if err := tx.Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+			Delete(&model.AnalyticsDailyPlatformStat{}).Error; err != nil {
+			return fmt.Errorf("清理平台日统计失败: %w", err)
+		}
+		if err := tx.CreateInBatches(platformStats, 500).Error; err != nil {
+			return fmt.Errorf("写入平台日统计失败: %w", err)
+		}
+
+		if err := tx.Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+			Delete(&model.AnalyticsDailyMerchantStat{}).Error; err != nil {
+			return fmt.Errorf("清理商户日统计失败: %w", err)
+		}
+		if len(merchantStats) > 0 {
+			if err := tx.CreateInBatches(merchantStats, 500).Error; err != nil {
+				return fmt.Errorf("写入商户日统计失败: %w", err)
+			}
+		}
+
+		if err := tx.Where("date BETWEEN ? AND ?", r.StartDate(), r.EndDate()).
+			Delete(&model.AnalyticsDailyPaymentLinkStat{}).Error; err != nil {
+			return fmt.Errorf("清理支付链接日统计失败: %w", err)
+		}
+		if len(linkStats) > 0 {
+			if err := tx.CreateInBatches(linkStats, 500).Error; err != nil {
+				return fmt.Errorf("写入支付链接日统计失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// statsDate 将本地时区日期表达式转换为 SQL
+func statsDate(column string) string {
+	return fmt.Sprintf("(%s AT TIME ZONE '%s')::date", column, statsLocation.String())
+}
+
+// aggregateOrderStats 按日、按类型汇总订单
+func aggregateOrderStats(ctx context.Context, r DateRange) ([]model.AnalyticsDailyOrderStat, error) {
+	var stats []model.AnalyticsDailyOrderStat
+	if err := db.DB(db.WithReplica(ctx)).Model(&model.Order{}).
+		Select(statsDate("created_at")+` AS date, type,
+			COUNT(*) AS order_count,
+			COUNT(*) FILTER (WHERE status IN @settled) AS settled_count,
+			COALESCE(SUM(amount) FILTER (WHERE status IN @settled), 0) AS settled_amount,
+			COALESCE(SUM(fee) FILTER (WHERE status IN @settled), 0) AS fee,
+			COUNT(*) FILTER (WHERE status = @refund) AS refund_count,
+			COALESCE(SUM(amount) FILTER (WHERE status = @refund), 0) AS refund_amount,
+			COUNT(DISTINCT payer_user_id) FILTER (WHERE status IN @settled AND payer_user_id > 0) AS active_payers,
+			COUNT(DISTINCT payee_user_id) FILTER (WHERE status IN @settled AND payee_user_id > 0) AS active_payees`,
+			map[string]interface{}{"settled": settledStatuses, "refund": model.OrderStatusRefund}).
+		Where("created_at >= ? AND created_at < ?", r.StartTime(), r.EndTime()).
+		Group("1, 2").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("汇总订单日统计失败: %w", err)
+	}
+	return stats, nil
+}
+
+// aggregatePlatformStats 按日汇总平台指标，无数据的日期也会写入零值记录以推进统计进度
+func aggregatePlatformStats(ctx context.Context, r DateRange) ([]model.AnalyticsDailyPlatformStat, error) {
+	readDB := db.DB(db.WithReplica(ctx))
+
+	stats := make([]model.AnalyticsDailyPlatformStat, 0, r.Days())
+	index := make(map[string]*model.AnalyticsDailyPlatformStat, r.Days())
+	for d := r.Start; !d.After(r.End); d = d.AddDate(0, 0, 1) {
+		stats = append(stats, model.AnalyticsDailyPlatformStat{Date: d})
+	}
+	for i := range stats {
+		index[stats[i].Date.Format(DateLayout)] = &stats[i]
+	}
+	get := func(date time.Time) *model.AnalyticsDailyPlatformStat {
+		return index[date.Format(DateLayout)]
+	}
+
+	var orderStats []platformOrderStat
+	if err := readDB.Model(&model.Order{}).
+		Select(statsDate("created_at")+` AS date,
+			COUNT(*) AS settled_count,
+			COALESCE(SUM(amount), 0) AS settled_amount,
+			COALESCE(SUM(fee), 0) AS fee,
+			COUNT(DISTINCT payer_user_id) FILTER (WHERE payer_user_id > 0) AS active_payers`).
+		Where("created_at >= ? AND created_at < ? AND type IN ? AND status IN ?",
+			r.StartTime(), r.EndTime(), platformOrderTypes, settledStatuses).
+		Group("1").
+		Scan(&orderStats).Error; err != nil {
+		return nil, fmt.Errorf("汇总平台交易统计失败: %w", err)
+	}
+	for _, s := range orderStats {
+		if stat := get(s.Date); stat != nil {
+			stat.SettledCount = s.SettledCount
+			stat.SettledAmount = s.SettledAmount
+			stat.Fee = s.Fee
+			stat.ActivePayers = s.ActivePayers
+		}
+	}
+
+	var newUsers []dailyCount
+	if err := readDB.Model(&model.User{}).
+		Select(statsDate("created_at")+" AS date, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ?", r.StartTime(), r.EndTime()).
+		Group("1").
+		Scan(&newUsers).Error; err != nil {
+		return nil, fmt.Errorf("汇总新增用户失败: %w", err)
+	}
+	for _, s := range newUsers {
+		if stat := get(s.Date); stat != nil {
+			stat.NewUsers = s.Count
+		}
+	}
+
+	// 新增商户以用户创建第一个 API Key 的时间为准，已删除的 API Key 也计入
+	var newMerchants []dailyCount
+	if err := readDB.Table("(?) AS first_keys",
+		db.DB(ctx).Unscoped().Model(&model.MerchantAPIKey{}).
+			Select("user_id, MIN(created_at) AS created_at").
+			Group("user_id")).
+		Select(statsDate("first_keys.created_at")+" AS date, COUNT(*) AS count").
+		Where("first_keys.created_at >= ? AND first_keys.created_at < ?", r.StartTime(), r.EndTime()).
+		Group("1").
+		Scan(&newMerchants).Error; err != nil {
+		return nil, fmt.Errorf("汇总新增商户失败: %w", err)
+	}
+	for _, s := range newMerchants {
+		if stat := get(s.Date); stat != nil {
+			stat.NewMerchants = s.Count
+		}
+	}
+
+	var disputeStats []disputeDailyStat
+	if err := readDB.Model(&model.Dispute{}).
+		Select(statsDate("created_at")+` AS date,
+			COUNT(*) AS dispute_count,
+			COUNT(*) FILTER (WHERE status = ?) AS dispute_refund`, model.DisputeStatusRefund).
+		Where("created_at >= ? AND created_at < ?", r.StartTime(), r.EndTime()).
+		Group("1").
+		Scan(&disputeStats).Error; err != nil {
+		return nil, fmt.Errorf("汇总争议统计失败: %w", err)
+	}
+	for _, s := range disputeStats {
+		if stat := get(s.Date); stat != nil {
+			stat.DisputeCount = s.DisputeCount
+			stat.DisputeRefund = s.DisputeRefund
+		}
+	}
+
+	return stats, nil
+}
+
+// aggregateMerchantStats 按日、按商户应用汇总收款订单与争议
+func aggregateMerchantStats(ctx context.Context, r DateRange) ([]model.AnalyticsDailyMerchantStat, error) {
+	readDB := db.DB(db.WithReplica(ctx))
+
+	var stats []model.AnalyticsDailyMerchantStat
+	if err := readDB.Model(&model.Order{}).
+		Select(statsDate("created_at")+` AS date, client_id,
+			COUNT(*) AS order_count,
+			COUNT(*) FILTER (WHERE status = @pending) AS pending_count,
+			COUNT(*) FILTER (WHERE status = @expired) AS expired_count,
+			COUNT(*) FILTER (WHERE status = @failed) AS failed_count,
+			COUNT(*) FILTER (WHERE status IN @settled) AS settled_count,
+			COALESCE(SUM(amount) FILTER (WHERE status IN @settled), 0) AS settled_amount,
+			COALESCE(SUM(fee) FILTER (WHERE status IN @settled), 0) AS fee,
+			COUNT(*) FILTER (WHERE status = @refund) AS refund_count,
+			COALESCE(SUM(amount) FILTER (WHERE status = @refund), 0) AS refund_amount`,
+			map[string]interface{}{
+				"pending": model.OrderStatusPending,
+				"expired": model.OrderStatusExpired,
+				"failed":  model.OrderStatusFailed,
+				"settled": settledStatuses,
+				"refund":  model.OrderStatusRefund,
+			}).
+		Where("created_at >= ? AND created_at < ? AND client_id <> '' AND type IN ?",
+			r.StartTime(), r.EndTime(), merchantOrderTypes).
+		Group("1, 2").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("汇总商户日统计失败: %w", err)
+	}
+
+	index := make(map[string]*model.AnalyticsDailyMerchantStat, len(stats))
+	for i := range stats {
+		index[stats[i].Date.Format(DateLayout)+"|"+stats[i].ClientID] = &stats[i]
+	}
+
+	// 争议按发起日期归属到订单所属应用
+	var disputeStats []struct {
+		Date         time.Time
+		ClientID     string
+		DisputeCount int64
+	}
+	if err := readDB.Model(&model.Dispute{}).
+		Select(statsDate("disputes.created_at")+" AS date, orders.client_id, COUNT(*) AS dispute_count").
+		Joins("JOIN orders ON disputes.order_id = orders.id").
+		Where("disputes.created_at >= ? AND disputes.created_at < ? AND orders.client_id <> ''", r.StartTime(), r.EndTime()).
+		Group("1, 2").
+		Scan(&disputeStats).Error; err != nil {
+		return nil, fmt.Errorf("汇总商户争议统计失败: %w", err)
+	}
+	for _, s := range disputeStats {
+		key := s.Date.Format(DateLayout) + "|" + s.ClientID
+		if stat, ok := index[key]; ok {
+			stat.DisputeCount = s.DisputeCount
+			continue
+		}
+		stats = append(stats, model.AnalyticsDailyMerchantStat{Date: s.Date, ClientID: s.ClientID, DisputeCount: s.DisputeCount})
+	}
+
+	return stats, nil
+}
+
+// aggregatePaymentLinkStats 按日、按支付链接汇总成交订单
+func aggregatePaymentLinkStats(ctx context.Context, r DateRange) ([]model.AnalyticsDailyPaymentLinkStat, error) {
+	var stats []model.AnalyticsDailyPaymentLinkStat
+	if err := db.DB(db.WithReplica(ctx)).Model(&model.Order{}).
+		Select(statsDate("created_at")+` AS date, payment_link_id, MAX(client_id) AS client_id,
+			COUNT(*) AS settled_count,
+			COALESCE(SUM(amount), 0) AS settled_amount,
+			COALESCE(SUM(fee), 0) AS fee`).
+		Where("created_at >= ? AND created_at < ? AND payment_link_id IS NOT NULL AND status IN ?",
+			r.StartTime(), r.EndTime(), settledStatuses).
+		Group("1, 2").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("汇总支付链接日统计失败: %w", err)
+	}
+	return stats, nil
+}
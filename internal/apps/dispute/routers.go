@@ -18,93 +18,118 @@ package dispute
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/util"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-// ListDisputesRequest 查询争议列表请求
+// ListDisputesRequest 查询争议列表请求，支持分页与游标两种模式
 type ListDisputesRequest struct {
-	Page      int     `json:"page" form:"page" binding:"min=1"`
-	PageSize  int     `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	util.PageRequest
 	Status    string  `json:"status" form:"status" binding:"omitempty,oneof=disputing refund closed"`
 	DisputeID *uint64 `json:"dispute_id" form:"dispute_id" binding:"omitempty"`
 }
 
+// DisputeItem 争议列表中的单条争议
+type DisputeItem struct {
+	model.Dispute
+	OrderName     string          `json:"order_name"`
+	PayeeUsername string          `json:"payee_username"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
 // ListDisputesResponse 查询争议列表响应
 type ListDisputesResponse struct {
-	Total    int64 `json:"total"`
-	Page     int   `json:"page"`
-	PageSize int   `json:"page_size"`
-	Disputes []struct {
-		model.Dispute
-		OrderName     string          `json:"order_name"`
-		PayeeUsername string          `json:"payee_username"`
-		Amount        decimal.Decimal `json:"amount"`
-	} `json:"disputes"`
+	util.PageResponse
+	Disputes []DisputeItem `json:"disputes"`
 }
 
-// ListDisputes 查询当前用户作为发起者的争议订单
-// @Tags order
-// @Accept json
-// @Produce json
-// @Param request body ListDisputesRequest false "request body"
-// @Success 200 {object} util.ResponseAny
-// @Router /api/v1/order/disputes [post]
-func ListDisputes(c *gin.Context) {
+// listDisputes 按指定视角查询争议列表，scope 为限定当前用户的条件
+func listDisputes(c *gin.Context, scope string, userID uint64) {
 	var req ListDisputesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
-	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
-
-	baseQuery := db.DB(c.Request.Context()).Model(&model.Dispute{}).
-		Select("disputes.*, orders.order_name, payee_user.username as payee_username, orders.amount, initiator_user.username as initiator_username, handler_user.username as handler_username").
-		Joins("JOIN orders ON disputes.order_id = orders.id").
-		Joins("JOIN users as payee_user ON orders.payee_user_id = payee_user.id").
-		Joins("JOIN users as initiator_user ON disputes.initiator_user_id = initiator_user.id").
-		Joins("LEFT JOIN users as handler_user ON disputes.handler_user_id = handler_user.id").
-		Where("disputes.initiator_user_id = ?", user.ID)
+	queryDisputes(c, &req, func(query *gorm.DB) *gorm.DB {
+		return query.Where(scope, userID)
+	})
+}
 
-	if req.Status != "" {
-		baseQuery = baseQuery.Where("disputes.status = ?", model.DisputeStatus(req.Status))
-	}
-	if req.DisputeID != nil {
-		baseQuery = baseQuery.Where("disputes.id = ?", req.DisputeID)
+// queryDisputes 按请求条件分页查询争议列表，scope 为空时不限定用户
+func queryDisputes(c *gin.Context, req *ListDisputesRequest, scope func(query *gorm.DB) *gorm.DB) {
+	filter := func(query *gorm.DB) *gorm.DB {
+		query = query.Joins("JOIN orders ON disputes.order_id = orders.id")
+		if scope != nil {
+			query = scope(query)
+		}
+		if req.Status != "" {
+			query = query.Where("disputes.status = ?", model.DisputeStatus(req.Status))
+		}
+		if req.DisputeID != nil {
+			query = query.Where("disputes.id = ?", req.DisputeID)
+		}
+		return query
 	}
 
-	var total int64
-	if err := baseQuery.Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
-		return
+	var total *int64
+	if req.NeedTotal() {
+		var count int64
+		if err := filter(db.DB(c.Request.Context()).Model(&model.Dispute{})).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		total = &count
 	}
 
-	response := &ListDisputesResponse{
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+	listQuery, err := req.Paginate(filter(db.DB(c.Request.Context()).Model(&model.Dispute{})).
+		Select("disputes.*, orders.order_name, payee_user.username as payee_username, orders.amount, initiator_user.username as initiator_username, handler_user.username as handler_username").
+		Joins("JOIN users as payee_user ON orders.payee_user_id = payee_user.id").
+		Joins("JOIN users as initiator_user ON disputes.initiator_user_id = initiator_user.id").
+		Joins("LEFT JOIN users as handler_user ON disputes.handler_user_id = handler_user.id"), "disputes")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
 	}
 
-	offset := (req.Page - 1) * req.PageSize
-	if err := baseQuery.Order("disputes.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Disputes).Error; err != nil {
+	response := &ListDisputesResponse{}
+	if err := listQuery.Find(&response.Disputes).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	response.PageResponse = util.BuildPageResponse(&req.PageRequest, total, &response.Disputes, func(item *DisputeItem) (time.Time, uint64) {
+		return item.CreatedAt, item.ID
+	})
 
 	c.JSON(http.StatusOK, util.OK(response))
 }
 
+// ListDisputes 查询当前用户作为发起者的争议订单
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body ListDisputesRequest false "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/disputes [post]
+func ListDisputes(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	listDisputes(c, "disputes.initiator_user_id = ?", user.ID)
+}
+
 // ListMerchantDisputes 查询当前用户作为商家的争议订单
 // @Tags order
 // @Accept json
@@ -113,48 +138,8 @@ func ListDisputes(c *gin.Context) {
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/order/disputes/merchant [post]
 func ListMerchantDisputes(c *gin.Context) {
-	var req ListDisputesRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
-		return
-	}
-
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
-
-	baseQuery := db.DB(c.Request.Context()).Model(&model.Dispute{}).
-		Select("disputes.*, orders.order_name, payee_user.username as payee_username, orders.amount, initiator_user.username as initiator_username, handler_user.username as handler_username").
-		Joins("JOIN orders ON disputes.order_id = orders.id").
-		Joins("JOIN users as payee_user ON orders.payee_user_id = payee_user.id").
-		Joins("JOIN users as initiator_user ON disputes.initiator_user_id = initiator_user.id").
-		Joins("LEFT JOIN users as handler_user ON disputes.handler_user_id = handler_user.id").
-		Where("orders.payee_user_id = ?", user.ID)
-
-	if req.Status != "" {
-		baseQuery = baseQuery.Where("disputes.status = ?", model.DisputeStatus(req.Status))
-	}
-	if req.DisputeID != nil {
-		baseQuery = baseQuery.Where("disputes.id = ?", req.DisputeID)
-	}
-
-	var total int64
-	if err := baseQuery.Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
-		return
-	}
-
-	response := &ListDisputesResponse{
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
-	}
-
-	offset := (req.Page - 1) * req.PageSize
-	if err := baseQuery.Order("disputes.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Disputes).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
-		return
-	}
-
-	c.JSON(http.StatusOK, util.OK(response))
+	listDisputes(c, "orders.payee_user_id = ?", user.ID)
 }
 
 // CreateDisputeRequest 发起争议请求
@@ -193,8 +178,11 @@ func CreateDispute(c *gin.Context) {
 		Status:          model.DisputeStatusDisputing,
 	}
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(),
 		func(tx *gorm.DB) error {
+			notifications = nil
+
 			var order model.Order
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND payer_user_id = ? AND status = ? AND type = ?", req.OrderID, user.ID, model.OrderStatusSuccess, model.OrderTypePayment).
@@ -221,6 +209,18 @@ func CreateDispute(c *gin.Context) {
 				return err
 			}
 
+			if err := notifications.Create(tx, &model.Notification{
+				UserID:    order.PayeeUserID,
+				Category:  model.NotificationCategoryDispute,
+				Type:      model.NotificationTypeDisputeOpened,
+				Title:     "收到争议",
+				Content:   fmt.Sprintf("%s 对订单「%s」发起了争议：%s", user.Username, order.OrderName, req.Reason),
+				OrderID:   &order.ID,
+				DisputeID: &dispute.ID,
+			}); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	); err != nil {
@@ -231,12 +231,17 @@ func CreateDispute(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(DisputeTimeWindowExpired))
 		} else if strings.Contains(errMsg, "SQLSTATE 23505") {
 			c.JSON(http.StatusBadRequest, util.Err(DuplicateDispute))
+		} else if errMsg == common.Busy {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	model.PublishOrderStatus(c.Request.Context(), req.OrderID, model.OrderStatusDisputing)
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, util.OK(dispute))
 }
 
@@ -269,8 +274,12 @@ func RefundReview(c *gin.Context) {
 
 	merchantUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	var orderID, payerUserID uint64
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(),
 		func(tx *gorm.DB) error {
+			notifications = nil
+
 			var dispute model.Dispute
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND status = ?", req.DisputeID, model.DisputeStatusDisputing).
@@ -290,6 +299,8 @@ func RefundReview(c *gin.Context) {
 				}
 				return err
 			}
+			orderID = order.ID
+			payerUserID = order.PayerUserID
 
 			if status == model.DisputeStatusRefund {
 				var payerUser model.User
@@ -338,6 +349,29 @@ func RefundReview(c *gin.Context) {
 					UpdateColumn("status", model.OrderStatusRefund).Error; err != nil {
 					return err
 				}
+
+				if err := tx.Create(&model.OrderRefund{
+					OrderID:        order.ID,
+					ClientID:       order.ClientID,
+					Amount:         order.Amount,
+					Source:         model.RefundSourceDispute,
+					DisputeID:      &dispute.ID,
+					OperatorUserID: merchantUser.ID,
+				}).Error; err != nil {
+					return err
+				}
+
+				if err := notifications.Create(tx, &model.Notification{
+					UserID:    payerUser.ID,
+					Category:  model.NotificationCategoryRefund,
+					Type:      model.NotificationTypeRefundReceived,
+					Title:     "争议已退款",
+					Content:   fmt.Sprintf("%s 同意了订单「%s」的退款，金额 %s", merchantUser.Username, order.OrderName, order.Amount.StringFixed(2)),
+					OrderID:   &order.ID,
+					DisputeID: &dispute.ID,
+				}); err != nil {
+					return err
+				}
 			} else if status == model.DisputeStatusClosed {
 				updateData := map[string]interface{}{
 					"status":          model.DisputeStatusClosed,
@@ -356,6 +390,18 @@ func RefundReview(c *gin.Context) {
 					UpdateColumn("status", model.OrderStatusRefused).Error; err != nil {
 					return err
 				}
+
+				if err := notifications.Create(tx, &model.Notification{
+					UserID:    dispute.InitiatorUserID,
+					Category:  model.NotificationCategoryDispute,
+					Type:      model.NotificationTypeDisputeRefused,
+					Title:     "争议被拒绝",
+					Content:   fmt.Sprintf("%s 拒绝了订单「%s」的退款：%s", merchantUser.Username, order.OrderName, req.Reason),
+					OrderID:   &order.ID,
+					DisputeID: &dispute.ID,
+				}); err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -364,12 +410,22 @@ func RefundReview(c *gin.Context) {
 		errMsg := err.Error()
 		if errMsg == DisputeNotFound {
 			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else if errMsg == common.Busy {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	if status == model.DisputeStatusRefund {
+		model.InvalidateUserCache(c.Request.Context(), merchantUser.ID, payerUserID)
+		model.PublishOrderStatus(c.Request.Context(), orderID, model.OrderStatusRefund)
+	} else {
+		model.PublishOrderStatus(c.Request.Context(), orderID, model.OrderStatusRefused)
+	}
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -394,8 +450,12 @@ func CloseDispute(c *gin.Context) {
 
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	var orderID uint64
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(),
 		func(tx *gorm.DB) error {
+			notifications = nil
+
 			var dispute model.Dispute
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND initiator_user_id = ? AND status = ?", req.DisputeID, user.ID, model.DisputeStatusDisputing).
@@ -415,6 +475,7 @@ func CloseDispute(c *gin.Context) {
 				}
 				return err
 			}
+			orderID = order.ID
 
 			if err := tx.Model(&model.Dispute{}).
 				Where("id = ?", dispute.ID).
@@ -431,6 +492,18 @@ func CloseDispute(c *gin.Context) {
 				return err
 			}
 
+			if err := notifications.Create(tx, &model.Notification{
+				UserID:    order.PayeeUserID,
+				Category:  model.NotificationCategoryDispute,
+				Type:      model.NotificationTypeDisputeClosed,
+				Title:     "争议已撤销",
+				Content:   fmt.Sprintf("%s 撤销了订单「%s」的争议", user.Username, order.OrderName),
+				OrderID:   &order.ID,
+				DisputeID: &dispute.ID,
+			}); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	); err != nil {
@@ -439,11 +512,229 @@ func CloseDispute(c *gin.Context) {
 			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
 		} else if errMsg == OrderNotFoundForDispute {
 			c.JSON(http.StatusNotFound, util.Err(OrderNotFoundForDispute))
+		} else if errMsg == common.Busy {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	model.PublishOrderStatus(c.Request.Context(), orderID, model.OrderStatusSuccess)
+	notifications.Dispatch(c.Request.Context())
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListAllDisputes 管理员查询全部争议
+// @Tags admin
+// @Produce json
+// @Param request query ListDisputesRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes [get]
+func ListAllDisputes(c *gin.Context) {
+	var req ListDisputesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	queryDisputes(c, &req, nil)
+}
+
+// ArbitrateDisputeRequest 管理员仲裁争议请求
+type ArbitrateDisputeRequest struct {
+	Status string `json:"status" binding:"required,oneof=refund closed"`
+	Reason string `json:"reason" binding:"omitempty,max=100"`
+}
+
+// ArbitrateDispute 管理员仲裁处理中的争议（退款/驳回）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "争议ID"
+// @Param request body ArbitrateDisputeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes/{id}/arbitrate [post]
+func ArbitrateDispute(c *gin.Context) {
+	disputeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidDisputeID))
+		return
+	}
+
+	var req ArbitrateDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	status := model.DisputeStatus(req.Status)
+
+	if status == model.DisputeStatusClosed && req.Reason == "" {
+		c.JSON(http.StatusBadRequest, util.Err(ReasonRequiredForRefusal))
+		return
+	}
+
+	adminUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var order model.Order
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(),
+		func(tx *gorm.DB) error {
+			notifications = nil
+
+			var dispute model.Dispute
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ?", disputeID, model.DisputeStatusDisputing).
+				First(&dispute).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(DisputeNotFound)
+				}
+				return err
+			}
+
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ? AND type = ?", dispute.OrderID, model.OrderStatusDisputing, model.OrderTypePayment).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFoundForDispute)
+				}
+				return err
+			}
+
+			if status == model.DisputeStatusClosed {
+				if err := tx.Model(&model.Dispute{}).
+					Where("id = ?", dispute.ID).
+					Updates(map[string]interface{}{
+						"status":          model.DisputeStatusClosed,
+						"handler_user_id": adminUser.ID,
+						"reason":          dispute.Reason + " [仲裁驳回理由: " + req.Reason + "]",
+					}).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Model(&model.Order{}).
+					Where("id = ?", order.ID).
+					UpdateColumn("status", model.OrderStatusRefused).Error; err != nil {
+					return err
+				}
+
+				return notifications.Create(tx, &model.Notification{
+					UserID:    dispute.InitiatorUserID,
+					Category:  model.NotificationCategoryDispute,
+					Type:      model.NotificationTypeDisputeRefused,
+					Title:     "争议被仲裁驳回",
+					Content:   fmt.Sprintf("平台仲裁驳回了订单「%s」的退款：%s", order.OrderName, req.Reason),
+					OrderID:   &order.ID,
+					DisputeID: &dispute.ID,
+				})
+			}
+
+			var payeeUser model.User
+			if err := payeeUser.GetByID(tx, order.PayeeUserID); err != nil {
+				return err
+			}
+
+			// 获取商家的支付配置
+			var merchantPayConfig model.UserPayConfig
+			if err := merchantPayConfig.GetByPayScore(tx, payeeUser.PayScore); err != nil {
+				return err
+			}
+
+			merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", order.PayeeUserID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance - ?", order.Amount),
+					"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
+					"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
+				}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", order.PayerUserID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", order.Amount),
+					"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
+					"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
+				}).Error; err != nil {
+				return err
+			}
+
+			updateData := map[string]interface{}{
+				"status":          model.DisputeStatusRefund,
+				"handler_user_id": adminUser.ID,
+			}
+			if req.Reason != "" {
+				updateData["reason"] = dispute.Reason + " [仲裁退款理由: " + req.Reason + "]"
+			}
+			if err := tx.Model(&model.Dispute{}).
+				Where("id = ?", dispute.ID).
+				Updates(updateData).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&model.Order{}).
+				Where("id = ?", order.ID).
+				UpdateColumn("status", model.OrderStatusRefund).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&model.OrderRefund{
+				OrderID:        order.ID,
+				ClientID:       order.ClientID,
+				Amount:         order.Amount,
+				Source:         model.RefundSourceArbitration,
+				DisputeID:      &dispute.ID,
+				OperatorUserID: adminUser.ID,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := notifications.Create(tx, &model.Notification{
+				UserID:    order.PayerUserID,
+				Category:  model.NotificationCategoryRefund,
+				Type:      model.NotificationTypeRefundReceived,
+				Title:     "争议已仲裁退款",
+				Content:   fmt.Sprintf("平台仲裁同意了订单「%s」的退款，金额 %s", order.OrderName, order.Amount.StringFixed(2)),
+				OrderID:   &order.ID,
+				DisputeID: &dispute.ID,
+			}); err != nil {
+				return err
+			}
+
+			return notifications.Create(tx, &model.Notification{
+				UserID:    order.PayeeUserID,
+				Category:  model.NotificationCategoryRefund,
+				Type:      model.NotificationTypeRefundIssued,
+				Title:     "争议已仲裁退款",
+				Content:   fmt.Sprintf("平台仲裁判定订单「%s」退款，已从余额扣除 %s", order.OrderName, order.Amount.StringFixed(2)),
+				OrderID:   &order.ID,
+				DisputeID: &dispute.ID,
+			})
+		},
+	); err != nil {
+		errMsg := err.Error()
+		if errMsg == DisputeNotFound {
+			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else if errMsg == OrderNotFoundForDispute {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFoundForDispute))
+		} else if errMsg == common.Busy {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	if status == model.DisputeStatusRefund {
+		model.InvalidateUserCache(c.Request.Context(), order.PayeeUserID, order.PayerUserID)
+		model.PublishOrderStatus(c.Request.Context(), order.ID, model.OrderStatusRefund)
+	} else {
+		model.PublishOrderStatus(c.Request.Context(), order.ID, model.OrderStatusRefused)
+	}
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
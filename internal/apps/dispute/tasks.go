@@ -28,6 +28,7 @@ import (
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/task"
 	"github.com/linux-do/pay/internal/task/schedule"
 	"gorm.io/gorm"
@@ -91,6 +92,82 @@ func HandleAutoRefundExpiredDisputes(ctx context.Context, t *asynq.Task) error {
 	return nil
 }
 
+// expiringDispute 即将超时的争议
+type expiringDispute struct {
+	ID          uint64
+	OrderID     uint64
+	OrderName   string
+	PayeeUserID uint64
+	CreatedAt   time.Time
+}
+
+// HandleRemindExpiringDisputes 提醒商户处理即将超时自动退款的争议，每个争议只提醒一次
+func HandleRemindExpiringDisputes(ctx context.Context, t *asynq.Task) error {
+	disputeTimeHours, errGet := model.GetIntByKey(ctx, model.ConfigKeyDisputeTimeWindowHours)
+	if errGet != nil {
+		logger.ErrorF(ctx, "获取争议时间窗口配置失败: %v", errGet)
+		return errGet
+	}
+
+	window := time.Duration(disputeTimeHours) * time.Hour
+	remindBefore := time.Duration(config.Config.Schedule.DisputeExpiringRemindHours) * time.Hour
+	now := time.Now()
+
+	// created_at 落在 (now - window, now - window + remindBefore] 内的争议将在 remindBefore 内超时
+	expiredBefore := now.Add(-window)
+	remindAfter := expiredBefore.Add(remindBefore)
+
+	pageSize := 200
+	lastID := uint64(0)
+	reminded := 0
+
+	for {
+		var disputes []expiringDispute
+		if err := db.DB(ctx).Model(&model.Dispute{}).
+			Select("disputes.id, disputes.order_id, disputes.created_at, orders.order_name, orders.payee_user_id").
+			Joins("JOIN orders ON disputes.order_id = orders.id").
+			Where("disputes.id > ? AND disputes.status = ? AND disputes.created_at > ? AND disputes.created_at <= ?",
+				lastID, model.DisputeStatusDisputing, expiredBefore, remindAfter).
+			Where("NOT EXISTS (SELECT 1 FROM notifications WHERE notifications.dispute_id = disputes.id AND notifications.type = ?)",
+				model.NotificationTypeDisputeExpiring).
+			Order("disputes.id ASC").
+			Limit(pageSize).
+			Scan(&disputes).Error; err != nil {
+			logger.ErrorF(ctx, "查询即将超时争议失败: %v", err)
+			return err
+		}
+
+		if len(disputes) == 0 {
+			break
+		}
+
+		var notifications service.PendingNotifications
+		for _, dispute := range disputes {
+			deadline := dispute.CreatedAt.Add(window)
+			if err := notifications.Create(db.DB(ctx), &model.Notification{
+				UserID:    dispute.PayeeUserID,
+				Category:  model.NotificationCategoryDispute,
+				Type:      model.NotificationTypeDisputeExpiring,
+				Title:     "争议即将自动退款",
+				Content:   fmt.Sprintf("订单「%s」的争议将于 %s 超时，超时后系统将自动退款", dispute.OrderName, deadline.Format(time.DateTime)),
+				OrderID:   &dispute.OrderID,
+				DisputeID: &dispute.ID,
+			}); err != nil {
+				logger.ErrorF(ctx, "创建争议[ID:%d]超时提醒失败: %v", dispute.ID, err)
+				notifications.Dispatch(ctx)
+				return err
+			}
+			reminded++
+		}
+		notifications.Dispatch(ctx)
+
+		lastID = disputes[len(disputes)-1].ID
+	}
+
+	logger.InfoF(ctx, "已提醒 %d 个即将超时的争议", reminded)
+	return nil
+}
+
 // HandleAutoRefundSingleDispute 处理单个争议的自动退款任务
 func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 	// 解析任务参数
@@ -101,7 +178,12 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("解析任务参数失败: %w", err)
 	}
 
-	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+	var refundedOrderID uint64
+	var refundedUserIDs []uint64
+	var notifications service.PendingNotifications
+	if err := db.Transaction(ctx, func(tx *gorm.DB) error {
+		notifications = nil
+
 		var dispute model.Dispute
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 			Where("id = ? AND status = ?", payload.DisputeID, model.DisputeStatusDisputing).
@@ -181,14 +263,56 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			return fmt.Errorf("更新订单状态失败: %w", err)
 		}
 
+		if err := tx.Create(&model.OrderRefund{
+			OrderID:   order.ID,
+			ClientID:  order.ClientID,
+			Amount:    order.Amount,
+			Source:    model.RefundSourceDisputeAuto,
+			DisputeID: &dispute.ID,
+		}).Error; err != nil {
+			return fmt.Errorf("记录退款失败: %w", err)
+		}
+
+		if err := notifications.Create(tx, &model.Notification{
+			UserID:    payerUser.ID,
+			Category:  model.NotificationCategoryRefund,
+			Type:      model.NotificationTypeRefundReceived,
+			Title:     "争议已自动退款",
+			Content:   fmt.Sprintf("商户未在时限内处理，订单「%s」已自动退款 %s", order.OrderName, order.Amount.StringFixed(2)),
+			OrderID:   &order.ID,
+			DisputeID: &dispute.ID,
+		}); err != nil {
+			return fmt.Errorf("创建付款方通知失败: %w", err)
+		}
+
+		if err := notifications.Create(tx, &model.Notification{
+			UserID:    payeeUser.ID,
+			Category:  model.NotificationCategoryRefund,
+			Type:      model.NotificationTypeRefundIssued,
+			Title:     "争议超时已自动退款",
+			Content:   fmt.Sprintf("订单「%s」的争议未在时限内处理，系统已自动退款 %s", order.OrderName, order.Amount.StringFixed(2)),
+			OrderID:   &order.ID,
+			DisputeID: &dispute.ID,
+		}); err != nil {
+			return fmt.Errorf("创建商户通知失败: %w", err)
+		}
+
 		logger.InfoF(ctx, "自动退款成功: 争议[ID:%d] 订单[ID:%d] 金额[%s] 付款方[%s] 商家[%s]",
 			dispute.ID, order.ID, order.Amount.String(), payerUser.Username, payeeUser.Username)
 
+		refundedOrderID = order.ID
+		refundedUserIDs = []uint64{payerUser.ID, payeeUser.ID}
 		return nil
 	}); err != nil {
 		logger.ErrorF(ctx, "处理争议[ID:%d]自动退款失败: %v", payload.DisputeID, err)
 		return err
 	}
 
+	if refundedOrderID > 0 {
+		model.InvalidateUserCache(ctx, refundedUserIDs...)
+		model.PublishOrderStatus(ctx, refundedOrderID, model.OrderStatusRefund)
+	}
+	notifications.Dispatch(ctx)
+
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/merchant"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/audit"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
@@ -172,6 +173,9 @@ func UpdateAPIKey(c *gin.Context) {
 func DeleteAPIKey(c *gin.Context) {
 	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
 
+	audit.SetTarget(c, audit.TargetMerchantAPIKey, apiKey.ID)
+	audit.SetBefore(c, apiKey)
+
 	if err := db.DB(c.Request.Context()).Delete(&apiKey).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
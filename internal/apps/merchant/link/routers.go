@@ -250,7 +250,7 @@ func PayByLink(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	if err := db.TransactionOnce(c.Request.Context(),
 		func(tx *gorm.DB) error {
 			// 检查每日限额
 			if err := service.CheckDailyLimit(tx, currentUser.ID, paymentLink.Amount, payerPayConfig.DailyLimit); err != nil {
@@ -258,7 +258,7 @@ func PayByLink(c *gin.Context) {
 			}
 
 			// 计算手续费
-			_, merchantAmount, feePercent := service.CalculateFee(paymentLink.Amount, merchantPayConfig.FeeRate)
+			fee, merchantAmount, feePercent := service.CalculateFee(paymentLink.Amount, merchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
 
 			remark := req.Remark
@@ -270,16 +270,18 @@ func PayByLink(c *gin.Context) {
 
 			// 创建订单
 			order := model.Order{
-				OrderName:   paymentLink.ProductName,
-				PayerUserID: currentUser.ID,
-				PayeeUserID: merchantUser.ID,
-				ClientID:    merchantAPIKey.ClientID,
-				Amount:      paymentLink.Amount,
-				Status:      model.OrderStatusSuccess,
-				Type:        model.OrderTypeOnline,
-				Remark:      remark,
-				TradeTime:   time.Now(),
-				ExpiresAt:   time.Now(),
+				OrderName:     paymentLink.ProductName,
+				PayerUserID:   currentUser.ID,
+				PayeeUserID:   merchantUser.ID,
+				ClientID:      merchantAPIKey.ClientID,
+				PaymentLinkID: &paymentLink.ID,
+				Amount:        paymentLink.Amount,
+				Fee:           fee,
+				Status:        model.OrderStatusSuccess,
+				Type:          model.OrderTypeOnline,
+				Remark:        remark,
+				TradeTime:     time.Now(),
+				ExpiresAt:     time.Now(),
 			}
 			if err := tx.Create(&order).Error; err != nil {
 				return err
@@ -318,11 +320,15 @@ func PayByLink(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(common.InsufficientBalance))
 		case common.DailyLimitExceeded:
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		case common.Busy:
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
 		default:
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	model.InvalidateUserCache(c.Request.Context(), currentUser.ID, merchantUser.ID)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
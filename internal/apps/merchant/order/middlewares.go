@@ -0,0 +1,49 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// RequireOrder 加载当前应用下的订单
+func RequireOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+		var order model.Order
+		if err := db.DB(c.Request.Context()).
+			Select("orders.*, payer_user.username AS payer_username, payee_user.username AS payee_username").
+			Joins("LEFT JOIN users AS payer_user ON orders.payer_user_id = payer_user.id").
+			Joins("LEFT JOIN users AS payee_user ON orders.payee_user_id = payee_user.id").
+			Where("orders.id = ? AND orders.client_id = ?", c.Param("orderId"), apiKey.ClientID).
+			First(&order).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, util.Err(OrderNotFound))
+			return
+		}
+
+		util.SetToContext(c, OrderObjKey, &order)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package order
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ListOrdersRequest 查询应用订单列表请求
+type ListOrdersRequest struct {
+	util.PageRequest
+	Status      string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired closed disputing refund refused"`
+	OutTradeNo  string     `json:"out_trade_no" form:"out_trade_no" binding:"omitempty,max=64"`
+	Payer       string     `json:"payer" form:"payer" binding:"omitempty,max=64"`
+	PayerUserID uint64     `json:"payer_user_id" form:"payer_user_id"`
+	StartTime   *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
+	EndTime     *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
+	Keyword     string     `json:"keyword" form:"keyword" binding:"omitempty,max=64"`
+}
+
+// OrderItem 应用订单列表中的单条订单
+type OrderItem struct {
+	model.Order
+	DisputeID *uint64 `json:"dispute_id"`
+}
+
+// ListOrdersResponse 查询应用订单列表响应
+type ListOrdersResponse struct {
+	util.PageResponse
+	Orders []OrderItem `json:"orders"`
+}
+
+// OrderDetailResponse 订单详情，包含回调记录、退款和争议
+type OrderDetailResponse struct {
+	Order      *model.Order              `json:"order"`
+	Dispute    *model.Dispute            `json:"dispute"`
+	Refunds    []model.OrderRefund       `json:"refunds"`
+	NotifyLogs []model.MerchantNotifyLog `json:"notify_logs"`
+}
+
+// ListOrders 获取应用下的订单列表
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request query ListOrdersRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/orders [get]
+func ListOrders(c *gin.Context) {
+	var req ListOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	filter := func(query *gorm.DB) *gorm.DB {
+		query = query.Where("orders.client_id = ?", apiKey.ClientID)
+		if req.Status != "" {
+			query = query.Where("orders.status = ?", model.OrderStatus(req.Status))
+		}
+		if req.OutTradeNo != "" {
+			query = query.Where("orders.merchant_order_no = ?", req.OutTradeNo)
+		}
+		if req.PayerUserID > 0 {
+			query = query.Where("orders.payer_user_id = ?", req.PayerUserID)
+		}
+		if req.Payer != "" {
+			query = query.Where("orders.payer_user_id = (SELECT id FROM users WHERE username = ?)", req.Payer)
+		}
+		if req.StartTime != nil {
+			query = query.Where("orders.created_at >= ?", req.StartTime)
+		}
+		if req.EndTime != nil {
+			query = query.Where("orders.created_at <= ?", req.EndTime)
+		}
+		return service.ApplyOrderKeywordFilter(query, apiKey.UserID, req.Keyword)
+	}
+
+	var total *int64
+	if req.NeedTotal() {
+		var count int64
+		if err := filter(db.DB(c.Request.Context()).Model(&model.Order{})).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		total = &count
+	}
+
+	listQuery, err := req.Paginate(filter(db.DB(c.Request.Context()).Model(&model.Order{})).
+		Select("orders.*, payer_user.username AS payer_username, disputes.id AS dispute_id").
+		Joins("LEFT JOIN users AS payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN disputes ON orders.id = disputes.order_id"), "orders")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListOrdersResponse{}
+	if err := listQuery.Find(&response.Orders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	response.PageResponse = util.BuildPageResponse(&req.PageRequest, total, &response.Orders, func(item *OrderItem) (time.Time, uint64) {
+		return item.CreatedAt, item.ID
+	})
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetOrder 获取应用订单详情
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param orderId path uint64 true "订单ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/orders/{orderId} [get]
+func GetOrder(c *gin.Context) {
+	order, _ := util.GetFromContext[*model.Order](c, OrderObjKey)
+
+	response := &OrderDetailResponse{Order: order}
+
+	var dispute model.Dispute
+	if err := db.DB(c.Request.Context()).Where("order_id = ?", order.ID).First(&dispute).Error; err == nil {
+		response.Dispute = &dispute
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Where("order_id = ?", order.ID).
+		Order("id ASC").
+		Find(&response.Refunds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Where("order_id = ?", order.ID).
+		Order("id DESC").
+		Limit(NotifyLogsLimit).
+		Find(&response.NotifyLogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// CloseOrder 关闭应用下待支付的订单
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param orderId path uint64 true "订单ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/orders/{orderId}/close [post]
+func CloseOrder(c *gin.Context) {
+	order, _ := util.GetFromContext[*model.Order](c, OrderObjKey)
+
+	result := db.DB(c.Request.Context()).Model(&model.Order{}).
+		Where("id = ? AND status = ?", order.ID, model.OrderStatusPending).
+		UpdateColumn("status", model.OrderStatusClosed)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, util.Err(OrderNotClosable))
+		return
+	}
+
+	// 订单已关闭，过期监听无需再处理
+	if err := db.Redis.Del(c.Request.Context(), db.PrefixedKey(fmt.Sprintf(payment.OrderExpireKeyFormat, order.ID))).Err(); err != nil {
+		logger.ErrorF(c.Request.Context(), "删除订单[ID:%d]过期 Key 失败: %v", order.ID, err)
+	}
+
+	model.PublishOrderStatus(c.Request.Context(), order.ID, model.OrderStatusClosed)
+
+	audit.SetTarget(c, audit.TargetOrder, order.ID)
+	audit.SetBefore(c, map[string]model.OrderStatus{"status": model.OrderStatusPending})
+	audit.SetAfter(c, map[string]model.OrderStatus{"status": model.OrderStatusClosed})
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
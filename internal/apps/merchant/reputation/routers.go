@@ -0,0 +1,149 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reputation
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ListReputationsRequest 查询商户信誉列表请求
+type ListReputationsRequest struct {
+	Page       int    `json:"page" form:"page" binding:"min=1"`
+	PageSize   int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	TrustBadge string `json:"trust_badge" form:"trust_badge" binding:"omitempty,oneof=new trusted normal risky"`
+	OrderBy    string `json:"order_by" form:"order_by" binding:"omitempty,oneof=dispute_rate refund_rate order_count auto_refund_count"`
+}
+
+// ListReputationsResponse 查询商户信誉列表响应
+type ListReputationsResponse struct {
+	Total       int64                      `json:"total"`
+	Page        int                        `json:"page"`
+	PageSize    int                        `json:"page_size"`
+	Reputations []model.MerchantReputation `json:"reputations"`
+}
+
+// ListReputations 获取商户信誉列表
+// @Tags admin
+// @Produce json
+// @Param request query ListReputationsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/merchant-reputations [get]
+func ListReputations(c *gin.Context) {
+	var req ListReputationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.MerchantReputation{}).
+		Select("merchant_reputations.*, users.username").
+		Joins("LEFT JOIN users ON merchant_reputations.user_id = users.id")
+
+	if req.TrustBadge != "" {
+		baseQuery = baseQuery.Where("merchant_reputations.trust_badge = ?", model.TrustBadge(req.TrustBadge))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	orderBy := req.OrderBy
+	if orderBy == "" {
+		orderBy = "dispute_rate"
+	}
+
+	response := &ListReputationsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Order("merchant_reputations." + orderBy + " DESC").
+		Order("merchant_reputations.user_id ASC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&response.Reputations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetReputation 获取单个商户信誉
+// @Tags admin
+// @Produce json
+// @Param user_id path uint64 true "商户用户ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/merchant-reputations/{user_id} [get]
+func GetReputation(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidUserID))
+		return
+	}
+
+	var reputation model.MerchantReputation
+	if err := db.DB(c.Request.Context()).
+		Select("merchant_reputations.*, users.username").
+		Joins("LEFT JOIN users ON merchant_reputations.user_id = users.id").
+		Where("merchant_reputations.user_id = ?", userID).
+		First(&reputation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ReputationNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(reputation))
+}
+
+// RefreshReputations 立即下发商户信誉刷新任务
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/merchant-reputations/refresh [post]
+func RefreshReputations(c *gin.Context) {
+	if _, err := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.RefreshMerchantReputationsTask, nil),
+		asynq.MaxRetry(3),
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	audit.SetTarget(c, audit.TargetMerchantReputation, "all")
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
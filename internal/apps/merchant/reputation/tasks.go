@@ -0,0 +1,125 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reputation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// orderStat 商户订单统计
+type orderStat struct {
+	UserID      uint64
+	OrderCount  int64
+	RefundCount int64
+}
+
+// disputeStat 商户争议统计
+type disputeStat struct {
+	UserID              uint64
+	DisputeCount        int64
+	DisputeLostCount    int64
+	AutoRefundCount     int64
+	HandledDisputeCount int64
+	AvgResponseSeconds  float64
+}
+
+// HandleRefreshMerchantReputations 重新计算所有商户的信誉
+func HandleRefreshMerchantReputations(ctx context.Context, t *asynq.Task) error {
+	// 统计已完成交易的商户订单（争议中、已退款、已拒绝退款的订单均视为已成交）
+	var orderStats []orderStat
+	if err := db.DB(ctx).Model(&model.Order{}).
+		Select("payee_user_id AS user_id, COUNT(*) AS order_count, COUNT(*) FILTER (WHERE status = ?) AS refund_count", model.OrderStatusRefund).
+		Where("type IN ? AND status IN ? AND payee_user_id > 0",
+			[]model.OrderType{model.OrderTypePayment, model.OrderTypeOnline},
+			[]model.OrderStatus{model.OrderStatusSuccess, model.OrderStatusDisputing, model.OrderStatusRefund, model.OrderStatusRefused}).
+		Group("payee_user_id").
+		Scan(&orderStats).Error; err != nil {
+		return fmt.Errorf("统计商户订单失败: %w", err)
+	}
+
+	// 统计商户争议：handler_user_id 为 0 表示系统自动退款，为商户本人表示商户主动处理
+	var disputeStats []disputeStat
+	if err := db.DB(ctx).Model(&model.Dispute{}).
+		Select(`orders.payee_user_id AS user_id,
+			COUNT(*) AS dispute_count,
+			COUNT(*) FILTER (WHERE disputes.status = ?) AS dispute_lost_count,
+			COUNT(*) FILTER (WHERE disputes.status = ? AND disputes.handler_user_id = 0) AS auto_refund_count,
+			COUNT(*) FILTER (WHERE disputes.handler_user_id = orders.payee_user_id) AS handled_dispute_count,
+			COALESCE(AVG(EXTRACT(EPOCH FROM disputes.updated_at - disputes.created_at)) FILTER (WHERE disputes.handler_user_id = orders.payee_user_id), 0) AS avg_response_seconds`,
+			model.DisputeStatusRefund, model.DisputeStatusRefund).
+		Joins("JOIN orders ON disputes.order_id = orders.id").
+		Group("orders.payee_user_id").
+		Scan(&disputeStats).Error; err != nil {
+		return fmt.Errorf("统计商户争议失败: %w", err)
+	}
+
+	reputations := make(map[uint64]*model.MerchantReputation, len(orderStats))
+	getReputation := func(userID uint64) *model.MerchantReputation {
+		if r, ok := reputations[userID]; ok {
+			return r
+		}
+		r := &model.MerchantReputation{UserID: userID}
+		reputations[userID] = r
+		return r
+	}
+
+	for _, stat := range orderStats {
+		r := getReputation(stat.UserID)
+		r.OrderCount = stat.OrderCount
+		r.RefundCount = stat.RefundCount
+	}
+	for _, stat := range disputeStats {
+		r := getReputation(stat.UserID)
+		r.DisputeCount = stat.DisputeCount
+		r.DisputeLostCount = stat.DisputeLostCount
+		r.AutoRefundCount = stat.AutoRefundCount
+		r.HandledDisputeCount = stat.HandledDisputeCount
+		r.AvgResponseSeconds = int64(stat.AvgResponseSeconds)
+	}
+
+	batch := make([]*model.MerchantReputation, 0, len(reputations))
+	for _, r := range reputations {
+		r.Calculate()
+		batch = append(batch, r)
+	}
+	if len(batch) == 0 {
+		logger.InfoF(ctx, "没有需要更新信誉的商户")
+		return nil
+	}
+
+	if err := db.DB(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"order_count", "dispute_count", "dispute_lost_count", "refund_count", "auto_refund_count",
+				"handled_dispute_count", "avg_response_seconds", "dispute_rate", "refund_rate", "trust_badge", "updated_at",
+			}),
+		}).
+		CreateInBatches(batch, 200).Error; err != nil {
+		return fmt.Errorf("更新商户信誉失败: %w", err)
+	}
+
+	logger.InfoF(ctx, "已更新 %d 个商户的信誉", len(batch))
+	return nil
+}
@@ -29,4 +29,11 @@ const (
 const (
 	OAuthStateCacheKeyFormat     = "oauth:state:%s"
 	OAuthStateCacheKeyExpiration = 10 * time.Minute
+	// UserSessionsCacheKeyFormat Redis Set key 格式，记录用户所有登录 Session ID
+	UserSessionsCacheKeyFormat = "oauth:user_sessions:%d"
+)
+
+const (
+	// DefaultSessionKeyPrefix 未配置 Redis 前缀时 Session Store 使用的默认 Key 前缀
+	DefaultSessionKeyPrefix = "session_"
 )
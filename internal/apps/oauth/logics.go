@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
@@ -48,6 +49,44 @@ func GetUserIDFromContext(c *gin.Context) uint64 {
 	return GetUserIDFromSession(session)
 }
 
+// SessionKeyPrefix 返回 Session 在 Redis 中的 Key 前缀，需与 Session Store 配置保持一致
+func SessionKeyPrefix() string {
+	if config.Config.Redis.KeyPrefix != "" {
+		return config.Config.Redis.KeyPrefix + "session:"
+	}
+	return DefaultSessionKeyPrefix
+}
+
+// TrackUserSession 记录用户的 Session ID，用于封禁时使其失效
+func TrackUserSession(ctx context.Context, userID uint64, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	key := db.PrefixedKey(fmt.Sprintf(UserSessionsCacheKeyFormat, userID))
+	if err := db.Redis.SAdd(ctx, key, sessionID).Err(); err != nil {
+		return err
+	}
+	return db.Redis.Expire(ctx, key, time.Duration(config.Config.App.SessionAge)*time.Second).Err()
+}
+
+// InvalidateUserSessions 删除用户所有登录 Session
+func InvalidateUserSessions(ctx context.Context, userID uint64) error {
+	key := db.PrefixedKey(fmt.Sprintf(UserSessionsCacheKeyFormat, userID))
+	sessionIDs, err := db.Redis.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	sessionKeyPrefix := SessionKeyPrefix()
+	for _, sessionID := range sessionIDs {
+		if err := db.Redis.Del(ctx, sessionKeyPrefix+sessionID).Err(); err != nil {
+			return err
+		}
+	}
+
+	return db.Redis.Del(ctx, key).Err()
+}
+
 func doOAuth(ctx context.Context, code string) (*model.User, error) {
 	// init trace
 	ctx, span := otel_trace.Start(ctx, "OAuth")
@@ -127,6 +166,7 @@ func doOAuth(ctx context.Context, code string) (*model.User, error) {
 	} else {
 		if user.ID != userInfo.Id {
 			// username 相同但 ID 不同(账户注销后被新用户占用)
+			model.InvalidateUserCache(ctx, user.ID)
 			if _, err = user.MarkAsDeactivatedAndCreateNew(ctx, &userInfo); err != nil {
 				span.SetStatus(codes.Error, err.Error())
 				return nil, err
@@ -144,5 +184,7 @@ func doOAuth(ctx context.Context, code string) (*model.User, error) {
 			}
 		}
 	}
+
+	model.InvalidateUserCache(ctx, user.ID)
 	return &user, nil
 }
@@ -20,7 +20,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/otel_trace"
@@ -40,11 +39,10 @@ func LoginRequired() gin.HandlerFunc {
 			return
 		}
 
-		// load user from db to make sure is active
-		var user model.User
-		tx := db.DB(ctx).Where("id = ? AND is_active = ?", userId, true).First(&user)
-		if tx.Error != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": tx.Error.Error(), "data": nil})
+		// load user from cache or primary to make sure is active
+		user, err := model.GetCachedActiveUser(ctx, userId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
 			return
 		}
 
@@ -52,7 +50,7 @@ func LoginRequired() gin.HandlerFunc {
 		logger.InfoF(ctx, "[LoginRequired] %d %s", user.ID, user.Username)
 
 		// set user info
-		util.SetToContext(c, UserObjKey, &user)
+		util.SetToContext(c, UserObjKey, user)
 
 		// next
 		c.Next()
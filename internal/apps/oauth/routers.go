@@ -87,6 +87,9 @@ func Callback(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	if err := TrackUserSession(c.Request.Context(), user.ID, session.ID()); err != nil {
+		logger.ErrorF(c.Request.Context(), "[OAuthCallback] 记录用户 Session 失败: %d %v", user.ID, err)
+	}
 	// response
 	c.JSON(http.StatusOK, util.OKNil())
 	logger.InfoF(c.Request.Context(), "[OAuthCallback] %d %s", user.ID, user.Username)
@@ -106,6 +109,7 @@ type BasicUserInfo struct {
 	PayScore         int64            `json:"pay_score"`
 	IsPayKey         bool             `json:"is_pay_key"`
 	IsAdmin          bool             `json:"is_admin"`
+	Permissions      []string         `json:"permissions"`
 	RemainQuota      decimal.Decimal  `json:"remain_quota"`
 	PayLevel         model.PayLevel   `json:"pay_level"`
 	DailyLimit       *int64           `json:"daily_limit"`
@@ -118,9 +122,11 @@ type BasicUserInfo struct {
 // @Router /api/v1/oauth/user-info [get]
 func UserInfo(c *gin.Context) {
 	user, _ := util.GetFromContext[*model.User](c, UserObjKey)
+	// 额度与权限常在支付、授权后立即刷新，统一从主库读取
+	ctx := db.WithPrimary(c.Request.Context())
 
 	var payConfig model.UserPayConfig
-	if err := payConfig.GetByPayScore(db.DB(c.Request.Context()), user.PayScore); err != nil {
+	if err := payConfig.GetByPayScore(db.DB(ctx), user.PayScore); err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
@@ -128,7 +134,7 @@ func UserInfo(c *gin.Context) {
 	// 计算剩余额度（-1 表示无限额）
 	remainQuota := decimal.NewFromInt(-1)
 	if payConfig.DailyLimit != nil && *payConfig.DailyLimit > 0 {
-		todayUsed, err := service.GetTodayUsedAmount(db.DB(c.Request.Context()), user.ID)
+		todayUsed, err := service.GetTodayUsedAmount(db.DB(ctx), user.ID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 			return
@@ -136,6 +142,16 @@ func UserInfo(c *gin.Context) {
 		remainQuota = decimal.NewFromInt(*payConfig.DailyLimit).Sub(todayUsed)
 	}
 
+	// 管理员返回权限列表，供前端控制菜单展示
+	permissions := []string{}
+	if user.IsAdmin {
+		var err error
+		if permissions, err = model.GetPermissionsByUserID(db.DB(ctx), user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+	}
+
 	c.JSON(
 		http.StatusOK,
 		util.OK(BasicUserInfo{
@@ -152,6 +168,7 @@ func UserInfo(c *gin.Context) {
 			PayScore:         user.PayScore,
 			IsPayKey:         user.PayKey != "",
 			IsAdmin:          user.IsAdmin,
+			Permissions:      permissions,
 			RemainQuota:      remainQuota,
 			PayLevel:         payConfig.Level,
 			DailyLimit:       payConfig.DailyLimit,
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import "github.com/linux-do/pay/internal/model"
+
+const (
+	// DownloadPath 签名下载地址，挂载在 API 前缀下且不要求登录
+	DownloadPath = "/v1/exports/download"
+	// TimeLayout 导出文件中的时间格式
+	TimeLayout = "2006-01-02 15:04:05"
+	// signSecretPlaceholder config.example.yaml 中 sign_secret 的占位值
+	signSecretPlaceholder = "<uniq string>"
+)
+
+// fileExtensions 导出格式对应的文件扩展名
+var fileExtensions = map[model.ExportFormat]string{
+	model.ExportFormatCSV:       ".csv",
+	model.ExportFormatXLSX:      ".xlsx",
+	model.ExportFormatBeancount: ".beancount",
+}
+
+// contentTypes 导出格式对应的下载类型
+var contentTypes = map[model.ExportFormat]string{
+	model.ExportFormatCSV:       "text/csv; charset=utf-8",
+	model.ExportFormatXLSX:      "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	model.ExportFormatBeancount: "text/plain; charset=utf-8",
+}
+
+// ledgerStatuses 资金已实际划转的订单状态，仅这些订单写入记账文件
+var ledgerStatuses = map[model.OrderStatus]bool{
+	model.OrderStatusSuccess:   true,
+	model.OrderStatusDisputing: true,
+	model.OrderStatusRefused:   true,
+}
+
+// exportHeaders 表格类导出的列名
+var exportHeaders = []string{
+	"订单号", "创建时间", "交易时间", "类型", "状态", "收支", "金额", "手续费",
+	"付款方", "收款方", "订单名称", "商户订单号", "应用", "备注",
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+const (
+	ExportNotFound           = "导出记录不存在"
+	ExportInProgress         = "已有正在进行的导出任务，请稍后再试"
+	ExportRangeRequired      = "请指定导出的开始时间和结束时间"
+	ExportRangeTooLarge      = "导出时间范围不能超过 %d 天"
+	ExportTooManyRows        = "导出记录数超过上限 %d 条，请缩小时间范围"
+	ExportNotReady           = "导出文件尚未生成"
+	ExportFileExpired        = "导出文件已过期，请重新导出"
+	InvalidDownloadSignature = "下载链接无效或已过期"
+)
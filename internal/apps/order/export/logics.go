@@ -0,0 +1,183 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linux-do/pay/internal/apps/order"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// exportRow 导出的单条交易
+type exportRow struct {
+	ID              uint64
+	OrderName       string
+	MerchantOrderNo string
+	ClientID        string
+	AppName         string
+	PayerUserID     uint64
+	PayeeUserID     uint64
+	PayerUsername   string
+	PayeeUsername   string
+	Amount          decimal.Decimal
+	Fee             decimal.Decimal
+	Status          model.OrderStatus
+	Type            model.OrderType
+	Remark          string
+	TradeTime       time.Time
+	CreatedAt       time.Time
+}
+
+// OrderNo 格式化订单号，与 model.Order 保持一致
+func (r *exportRow) OrderNo() string {
+	return fmt.Sprintf("%018d", r.ID)
+}
+
+// IsIncome 从导出者视角判断是否为收入
+func (r *exportRow) IsIncome(userID uint64) bool {
+	return r.PayeeUserID == userID
+}
+
+// DisplayType 从导出者视角展示的订单类型，收款方看到的 payment 订单显示为 receive
+func (r *exportRow) DisplayType(userID uint64) model.OrderType {
+	if r.Type == model.OrderTypePayment && r.PayeeUserID == userID {
+		return model.OrderTypeReceive
+	}
+	return r.Type
+}
+
+// Columns 表格类导出的单行数据，与 exportHeaders 一一对应
+func (r *exportRow) Columns(userID uint64) []string {
+	direction := "支出"
+	if r.IsIncome(userID) {
+		direction = "收入"
+	}
+	tradeTime := ""
+	if !r.TradeTime.IsZero() {
+		tradeTime = r.TradeTime.Local().Format(TimeLayout)
+	}
+	return []string{
+		r.OrderNo(),
+		r.CreatedAt.Local().Format(TimeLayout),
+		tradeTime,
+		string(r.DisplayType(userID)),
+		string(r.Status),
+		direction,
+		r.Amount.StringFixed(2),
+		r.Fee.StringFixed(2),
+		r.PayerUsername,
+		r.PayeeUsername,
+		r.OrderName,
+		r.MerchantOrderNo,
+		r.AppName,
+		r.Remark,
+	}
+}
+
+// exportQuery 根据导出记录构建订单查询，读取走只读副本
+func exportQuery(ctx context.Context, e *model.TransactionExport) *gorm.DB {
+	query := db.DB(db.WithReplica(ctx)).Model(&model.Order{}).
+		Select(`orders.id, orders.order_name, orders.merchant_order_no, orders.client_id,
+			merchant_api_keys.app_name, orders.payer_user_id, orders.payee_user_id,
+			payer_user.username AS payer_username, payee_user.username AS payee_username,
+			orders.amount, orders.fee, orders.status, orders.type, orders.remark,
+			orders.trade_time, orders.created_at`).
+		Joins("LEFT JOIN merchant_api_keys ON orders.client_id = merchant_api_keys.client_id").
+		Joins("LEFT JOIN users AS payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN users AS payee_user ON orders.payee_user_id = payee_user.id")
+
+	startTime, endTime := e.StartTime, e.EndTime
+	switch e.Scope {
+	case model.ExportScopeMerchant:
+		// 商户应用导出：应用下的全部订单
+		query = query.Where("orders.client_id = ?", e.ClientID).
+			Where("orders.created_at >= ? AND orders.created_at <= ?", startTime, endTime)
+		if e.OrderStatus != "" {
+			query = query.Where("orders.status = ?", e.OrderStatus)
+		}
+		query = service.ApplyOrderKeywordFilter(query, e.UserID, e.Keyword)
+	default:
+		query = order.ApplyTransactionFilter(query, e.UserID, order.TransactionFilter{
+			Type:      e.OrderType,
+			Status:    e.OrderStatus,
+			ClientID:  e.ClientID,
+			StartTime: &startTime,
+			EndTime:   &endTime,
+			Keyword:   e.Keyword,
+		})
+	}
+	return query
+}
+
+// downloadFileName 下载时展示的文件名
+func downloadFileName(e *model.TransactionExport) string {
+	return fmt.Sprintf("transactions_%s_%s%s",
+		e.StartTime.Local().Format("20060102"), e.EndTime.Local().Format("20060102"), fileExtensions[e.Format])
+}
+
+// ValidateSignSecret 校验下载链接签名密钥，未配置或仍为占位值时任何人都能伪造下载链接，API 服务拒绝启动
+func ValidateSignSecret() error {
+	secret := strings.TrimSpace(config.Config.Export.SignSecret)
+	if secret == "" || secret == signSecretPlaceholder {
+		return errors.New("export.sign_secret must be set to a random secret")
+	}
+	return nil
+}
+
+// sign 计算下载链接签名
+func sign(id uint64, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.Config.Export.SignSecret))
+	_, _ = fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature 校验下载链接签名与有效期
+func verifySignature(id uint64, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(sign(id, expires)), []byte(signature))
+}
+
+// BuildDownloadURL 生成导出文件的签名下载地址，有效期不超过文件保留时间
+func BuildDownloadURL(e *model.TransactionExport) (string, time.Time) {
+	expiresAt := time.Now().Add(time.Duration(config.Config.Export.DownloadURLTTLSeconds) * time.Second)
+	if e.FileExpiresAt != nil && e.FileExpiresAt.Before(expiresAt) {
+		expiresAt = *e.FileExpiresAt
+	}
+
+	query := url.Values{}
+	query.Set("id", strconv.FormatUint(e.ID, 10))
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", sign(e.ID, expiresAt.Unix()))
+	return config.Config.App.APIPrefix + DownloadPath + "?" + query.Encode(), expiresAt
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/apps/order"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// CreateExportRequest 创建交易导出请求，筛选条件与交易列表一致
+type CreateExportRequest struct {
+	Format string `json:"format" binding:"required,oneof=csv xlsx beancount"`
+	order.TransactionFilter
+}
+
+// CreateMerchantExportRequest 创建商户应用订单导出请求
+type CreateMerchantExportRequest struct {
+	Format    string     `json:"format" binding:"required,oneof=csv xlsx beancount"`
+	Status    string     `json:"status" binding:"omitempty,oneof=success pending failed expired closed disputing refund refused"`
+	StartTime *time.Time `json:"startTime" binding:"required"`
+	EndTime   *time.Time `json:"endTime" binding:"required,gtfield=StartTime"`
+	Keyword   string     `json:"keyword" binding:"omitempty,max=64"`
+}
+
+// ExportDetail 导出记录详情，文件可下载时附带签名下载地址
+type ExportDetail struct {
+	model.TransactionExport
+	DownloadURL          string     `json:"download_url,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty"`
+}
+
+// ListExportsRequest 查询导出记录请求
+type ListExportsRequest struct {
+	Page     int `json:"page" form:"page" binding:"min=1"`
+	PageSize int `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+}
+
+// ListExportsResponse 查询导出记录响应
+type ListExportsResponse struct {
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+	Exports  []model.TransactionExport `json:"exports"`
+}
+
+// DownloadExportRequest 签名下载请求
+type DownloadExportRequest struct {
+	ID        uint64 `form:"id" binding:"required"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// validateRange 校验导出时间范围
+func validateRange(startTime, endTime *time.Time) error {
+	if startTime == nil || endTime == nil {
+		return errors.New(ExportRangeRequired)
+	}
+	if maxDays := config.Config.Export.MaxRangeDays; maxDays > 0 && endTime.Sub(*startTime) > time.Duration(maxDays)*24*time.Hour {
+		return fmt.Errorf(ExportRangeTooLarge, maxDays)
+	}
+	return nil
+}
+
+// submitExport 保存导出记录并下发生成任务，同一用户同时只允许一个进行中的导出
+func submitExport(c *gin.Context, export *model.TransactionExport) {
+	ctx := c.Request.Context()
+
+	var inProgress int64
+	if err := db.DB(ctx).Model(&model.TransactionExport{}).
+		Where("user_id = ? AND status IN ?", export.UserID, []model.ExportStatus{model.ExportStatusPending, model.ExportStatusProcessing}).
+		Count(&inProgress).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if inProgress > 0 {
+		c.JSON(http.StatusTooManyRequests, util.Err(ExportInProgress))
+		return
+	}
+
+	export.Status = model.ExportStatusPending
+	if err := db.DB(ctx).Create(export).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	payload, _ := json.Marshal(ExportPayload{ExportID: export.ID})
+	if _, err := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.TransactionExportTask, payload),
+		asynq.MaxRetry(2),
+		asynq.Timeout(30*time.Minute),
+	); err != nil {
+		db.DB(ctx).Model(export).Updates(map[string]interface{}{
+			"status":        model.ExportStatusFailed,
+			"error_message": err.Error(),
+		})
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(export))
+}
+
+// CreateExport 创建个人交易导出
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body CreateExportRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/exports [post]
+func CreateExport(c *gin.Context) {
+	var req CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if err := validateRange(req.StartTime, req.EndTime); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	submitExport(c, &model.TransactionExport{
+		UserID:      user.ID,
+		Scope:       model.ExportScopeUser,
+		Format:      model.ExportFormat(req.Format),
+		OrderType:   req.Type,
+		OrderStatus: req.Status,
+		ClientID:    req.ClientID,
+		Keyword:     req.Keyword,
+		StartTime:   *req.StartTime,
+		EndTime:     *req.EndTime,
+	})
+}
+
+// CreateMerchantExport 创建商户应用订单导出
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request body CreateMerchantExportRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/exports [post]
+func CreateMerchantExport(c *gin.Context) {
+	var req CreateMerchantExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if err := validateRange(req.StartTime, req.EndTime); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	submitExport(c, &model.TransactionExport{
+		UserID:      apiKey.UserID,
+		Scope:       model.ExportScopeMerchant,
+		Format:      model.ExportFormat(req.Format),
+		OrderStatus: req.Status,
+		ClientID:    apiKey.ClientID,
+		Keyword:     req.Keyword,
+		StartTime:   *req.StartTime,
+		EndTime:     *req.EndTime,
+	})
+}
+
+// ListExports 获取当前用户的导出记录
+// @Tags order
+// @Produce json
+// @Param request query ListExportsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/exports [get]
+func ListExports(c *gin.Context) {
+	var req ListExportsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.TransactionExport{}).Where("user_id = ?", user.ID)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListExportsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Exports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetExport 获取导出记录，文件已生成时返回签名下载地址
+// @Tags order
+// @Produce json
+// @Param id path uint64 true "导出记录ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/exports/{id} [get]
+func GetExport(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var detail ExportDetail
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+		First(&detail.TransactionExport).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ExportNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if detail.Status == model.ExportStatusSuccess && detail.FileExpiresAt != nil && detail.FileExpiresAt.After(time.Now()) {
+		downloadURL, expiresAt := BuildDownloadURL(&detail.TransactionExport)
+		detail.DownloadURL = downloadURL
+		detail.DownloadURLExpiresAt = &expiresAt
+	}
+
+	c.JSON(http.StatusOK, util.OK(detail))
+}
+
+// DownloadExport 通过签名地址下载导出文件
+// @Tags order
+// @Produce octet-stream
+// @Param request query DownloadExportRequest true "request query"
+// @Success 200 {file} file
+// @Router /api/v1/exports/download [get]
+func DownloadExport(c *gin.Context) {
+	var req DownloadExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if !verifySignature(req.ID, req.Expires, req.Signature) {
+		c.JSON(http.StatusForbidden, util.Err(InvalidDownloadSignature))
+		return
+	}
+
+	var export model.TransactionExport
+	if err := db.DB(c.Request.Context()).Where("id = ?", req.ID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ExportNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	switch {
+	case export.Status == model.ExportStatusExpired,
+		export.Status == model.ExportStatusSuccess && export.FileExpiresAt != nil && export.FileExpiresAt.Before(time.Now()):
+		c.JSON(http.StatusGone, util.Err(ExportFileExpired))
+		return
+	case export.Status != model.ExportStatusSuccess:
+		c.JSON(http.StatusConflict, util.Err(ExportNotReady))
+		return
+	}
+
+	var file model.TransactionExportFile
+	if err := db.DB(c.Request.Context()).Where("export_id = ?", export.ID).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusGone, util.Err(ExportFileExpired))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.QueryEscape(export.FileName))
+	c.Data(http.StatusOK, contentTypes[export.Format], file.Content)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExportPayload 交易导出任务参数
+type ExportPayload struct {
+	ExportID uint64 `json:"export_id"`
+}
+
+// HandleTransactionExport 生成交易导出文件
+func HandleTransactionExport(ctx context.Context, t *asynq.Task) error {
+	var payload ExportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var export model.TransactionExport
+	if err := db.DB(ctx).
+		Where("id = ? AND status IN ?", payload.ExportID, []model.ExportStatus{model.ExportStatusPending, model.ExportStatusProcessing}).
+		First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.InfoF(ctx, "导出[ID:%d]已处理或不存在，跳过", payload.ExportID)
+			return nil
+		}
+		return err
+	}
+
+	if err := db.DB(ctx).Model(&export).Update("status", model.ExportStatusProcessing).Error; err != nil {
+		return err
+	}
+
+	if err := writeExport(ctx, &export); err != nil {
+		// 仅在最后一次重试失败时标记失败，避免用户看到中间状态
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried >= maxRetry {
+			message := err.Error()
+			if len(message) > 255 {
+				message = message[:255]
+			}
+			if errUpdate := db.DB(ctx).Model(&export).Updates(map[string]interface{}{
+				"status":        model.ExportStatusFailed,
+				"error_message": message,
+			}).Error; errUpdate != nil {
+				logger.ErrorF(ctx, "更新导出[ID:%d]失败状态失败: %v", export.ID, errUpdate)
+			}
+		}
+		return fmt.Errorf("生成导出[ID:%d]失败: %w", export.ID, err)
+	}
+
+	logger.InfoF(ctx, "导出[ID:%d]完成，共 %d 条记录", export.ID, export.RowCount)
+	return nil
+}
+
+// writeExport 流式查询订单并生成导出文件，文件内容与导出记录在同一事务中写入数据库
+func writeExport(ctx context.Context, export *model.TransactionExport) error {
+	var total int64
+	if err := exportQuery(ctx, export).Count(&total).Error; err != nil {
+		return err
+	}
+	if maxRows := config.Config.Export.MaxRows; maxRows > 0 && total > int64(maxRows) {
+		return fmt.Errorf(ExportTooManyRows, maxRows)
+	}
+
+	var buf bytes.Buffer
+	writer, err := newRowWriter(&buf, export)
+	if err != nil {
+		return err
+	}
+
+	query := exportQuery(ctx, export)
+	rows, err := query.Order("orders.created_at ASC").Order("orders.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rowCount int64
+	for rows.Next() {
+		var row exportRow
+		if err := query.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := writer.WriteRow(&row); err != nil {
+			return err
+		}
+		rowCount++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	fileExpiresAt := time.Now().Add(time.Duration(config.Config.Export.FileRetentionHours) * time.Hour)
+	export.Status = model.ExportStatusSuccess
+	export.RowCount = rowCount
+	export.FileName = downloadFileName(export)
+	export.FileSize = int64(buf.Len())
+	export.FileExpiresAt = &fileExpiresAt
+	return db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		// 重试时覆盖上一次写入的内容
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "export_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content", "created_at"}),
+		}).Create(&model.TransactionExportFile{ExportID: export.ID, Content: buf.Bytes()}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(export).Updates(map[string]interface{}{
+			"status":          export.Status,
+			"row_count":       export.RowCount,
+			"file_name":       export.FileName,
+			"file_size":       export.FileSize,
+			"file_expires_at": export.FileExpiresAt,
+			"error_message":   "",
+		}).Error
+	})
+}
+
+// HandleCleanupExpiredExports 删除超过保留时间的导出文件
+func HandleCleanupExpiredExports(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	cleaned := 0
+
+	for {
+		var exports []model.TransactionExport
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND file_expires_at <= ?", lastID, model.ExportStatusSuccess, time.Now()).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&exports).Error; err != nil {
+			logger.ErrorF(ctx, "查询过期导出失败: %v", err)
+			return err
+		}
+
+		if len(exports) == 0 {
+			break
+		}
+
+		for i := range exports {
+			if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+				if err := tx.Where("export_id = ?", exports[i].ID).Delete(&model.TransactionExportFile{}).Error; err != nil {
+					return err
+				}
+				return tx.Model(&exports[i]).Update("status", model.ExportStatusExpired).Error
+			}); err != nil {
+				logger.ErrorF(ctx, "清理导出[ID:%d]文件失败: %v", exports[i].ID, err)
+				continue
+			}
+			cleaned++
+		}
+
+		lastID = exports[len(exports)-1].ID
+	}
+
+	logger.InfoF(ctx, "已清理 %d 个过期导出文件", cleaned)
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/model"
+)
+
+// rowWriter 按格式逐行写出交易
+type rowWriter interface {
+	WriteRow(row *exportRow) error
+	Close() error
+}
+
+// newRowWriter 根据导出格式创建写入器
+func newRowWriter(w io.Writer, e *model.TransactionExport) (rowWriter, error) {
+	switch e.Format {
+	case model.ExportFormatCSV:
+		return newCSVWriter(w, e.UserID)
+	case model.ExportFormatXLSX:
+		return newXLSXWriter(w, e.UserID)
+	case model.ExportFormatBeancount:
+		return newBeancountWriter(w, e)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", e.Format)
+	}
+}
+
+// csvWriter CSV 导出，写入 UTF-8 BOM 以便 Excel 正确识别中文
+type csvWriter struct {
+	w      *csv.Writer
+	userID uint64
+}
+
+func newCSVWriter(w io.Writer, userID uint64) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w), userID: userID}
+	if err := cw.w.Write(exportHeaders); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(row *exportRow) error {
+	return cw.w.Write(row.Columns(cw.userID))
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxWriter 最小化的 XLSX 导出，工作表内容以流式写入 zip
+type xlsxWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	userID  uint64
+	rowNum  int
+	numeric map[int]bool
+}
+
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="transactions" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func newXLSXWriter(w io.Writer, userID uint64) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// 工作表必须最后创建，之后不能再写入其他 zip 条目
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxWriter{
+		zw:     zw,
+		sheet:  bufio.NewWriter(f),
+		userID: userID,
+		// 金额、手续费列写为数值单元格
+		numeric: map[int]bool{6: true, 7: true},
+	}
+	if _, err := xw.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	if err := xw.writeCells(exportHeaders, false); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) writeCells(cells []string, typed bool) error {
+	xw.rowNum++
+	var b strings.Builder
+	b.WriteString(`<row r="` + strconv.Itoa(xw.rowNum) + `">`)
+	for i, cell := range cells {
+		if typed && xw.numeric[i] {
+			b.WriteString(`<c><v>` + cell + `</v></c>`)
+			continue
+		}
+		b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&b, []byte(cell)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := xw.sheet.WriteString(b.String())
+	return err
+}
+
+func (xw *xlsxWriter) WriteRow(row *exportRow) error {
+	return xw.writeCells(row.Columns(xw.userID), true)
+}
+
+func (xw *xlsxWriter) Close() error {
+	if _, err := xw.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// beancountWriter Beancount 记账格式导出，同时兼容 ledger-cli 的基本语法
+type beancountWriter struct {
+	w         *bufio.Writer
+	userID    uint64
+	commodity string
+	account   string
+}
+
+func newBeancountWriter(w io.Writer, e *model.TransactionExport) (*beancountWriter, error) {
+	bw := &beancountWriter{
+		w:         bufio.NewWriter(w),
+		userID:    e.UserID,
+		commodity: config.Config.Export.BeancountCommodity,
+		account:   config.Config.Export.BeancountAccount,
+	}
+	if _, err := fmt.Fprintf(bw.w, "; %s 交易导出 %s ~ %s\noption \"operating_currency\" \"%s\"\n",
+		config.Config.App.AppName, e.StartTime.Local().Format(TimeLayout), e.EndTime.Local().Format(TimeLayout), bw.commodity); err != nil {
+		return nil, err
+	}
+	return bw, nil
+}
+
+// counterAccount 对手科目，按订单类型区分
+func (bw *beancountWriter) counterAccount(row *exportRow, income bool) string {
+	root := "Expenses"
+	if income {
+		root = "Income"
+	}
+	orderType := string(row.DisplayType(bw.userID))
+	return root + ":LinuxDo:" + strings.ToUpper(orderType[:1]) + orderType[1:]
+}
+
+func (bw *beancountWriter) WriteRow(row *exportRow) error {
+	// 待支付、失败、过期及已退款的订单不产生资金变动
+	if !ledgerStatuses[row.Status] {
+		return nil
+	}
+
+	date := row.TradeTime
+	if date.IsZero() {
+		date = row.CreatedAt
+	}
+	income := row.IsIncome(bw.userID)
+	payee := row.PayeeUsername
+	if income {
+		payee = row.PayerUsername
+	}
+
+	if _, err := fmt.Fprintf(bw.w, "\n%s * %s %s\n  order_no: \"%s\"\n",
+		date.Local().Format("2006-01-02"), strconv.Quote(payee), strconv.Quote(row.OrderName), row.OrderNo()); err != nil {
+		return err
+	}
+
+	var err error
+	if income {
+		// 收款方实收金额为订单金额扣除手续费
+		net := row.Amount.Sub(row.Fee)
+		_, err = fmt.Fprintf(bw.w, "  %s  %s %s\n", bw.account, net.StringFixed(2), bw.commodity)
+		if err == nil && row.Fee.IsPositive() {
+			_, err = fmt.Fprintf(bw.w, "  Expenses:LinuxDo:Fee  %s %s\n", row.Fee.StringFixed(2), bw.commodity)
+		}
+		if err == nil {
+			_, err = fmt.Fprintf(bw.w, "  %s  %s %s\n", bw.counterAccount(row, true), row.Amount.Neg().StringFixed(2), bw.commodity)
+		}
+	} else {
+		_, err = fmt.Fprintf(bw.w, "  %s  %s %s\n  %s  %s %s\n",
+			bw.account, row.Amount.Neg().StringFixed(2), bw.commodity,
+			bw.counterAccount(row, false), row.Amount.StringFixed(2), bw.commodity)
+	}
+	return err
+}
+
+func (bw *beancountWriter) Close() error {
+	return bw.w.Flush()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package order
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"gorm.io/gorm"
+)
+
+// TransactionFilter 交易筛选条件
+type TransactionFilter struct {
+	Type      string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community online adjustment"`
+	Status    string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired closed disputing refund refused"`
+	ClientID  string     `json:"client_id" form:"client_id" binding:"omitempty"`
+	StartTime *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
+	EndTime   *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
+	Keyword   string     `json:"keyword" form:"keyword" binding:"omitempty,max=64"`
+}
+
+// transactionListQuery 交易列表查询，关联应用、争议和双方用户信息
+func transactionListQuery(c *gin.Context) *gorm.DB {
+	return db.DB(c.Request.Context()).Model(&model.Order{}).
+		Select("orders.*, merchant_api_keys.app_name, merchant_api_keys.app_homepage_url, merchant_api_keys.app_description, merchant_api_keys.redirect_uri, disputes.id as dispute_id, payer_user.username as payer_username, payee_user.username as payee_username").
+		Joins("LEFT JOIN merchant_api_keys ON orders.client_id = merchant_api_keys.client_id").
+		Joins("LEFT JOIN disputes ON orders.id = disputes.order_id").
+		Joins("LEFT JOIN users as payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN users as payee_user ON orders.payee_user_id = payee_user.id")
+}
+
+// ApplyTransactionFilter 按用户视角为订单查询追加筛选条件
+func ApplyTransactionFilter(query *gorm.DB, userID uint64, filter TransactionFilter) *gorm.DB {
+	if filter.Type != "" {
+		orderType := model.OrderType(filter.Type)
+
+		switch orderType {
+		case model.OrderTypeReceive:
+			// receive 类型：查询当前用户作为收款方的 payment 订单
+			query = query.Where("orders.type = ? AND orders.payee_user_id = ?", model.OrderTypePayment, userID)
+		case model.OrderTypeCommunity:
+			// community 类型：查询当前用户作为收款方的 community 订单
+			query = query.Where("orders.type = ? AND orders.payee_user_id = ?", orderType, userID)
+		case model.OrderTypeOnline:
+			// online 类型：查询当前用户参与的 online 订单，client_id 仅在此范围内进一步筛选，不能越过用户范围查询他人应用的订单
+			query = query.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, userID, userID)
+		case model.OrderTypeAdjustment:
+			// adjustment 类型：查询当前用户被管理员调整余额的订单，对手方为系统
+			query = query.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, userID, userID)
+		case model.OrderTypePayment, model.OrderTypeTransfer:
+			// payment、transfer 类型：查询当前用户作为付款方的订单
+			query = query.Where("orders.type = ? AND orders.payer_user_id = ?", orderType, userID)
+		}
+	} else {
+		query = query.Where("orders.payee_user_id = ? OR orders.payer_user_id = ?", userID, userID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("orders.status = ?", model.OrderStatus(filter.Status))
+	}
+
+	if filter.ClientID != "" {
+		query = query.Where("orders.client_id = ?", filter.ClientID)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("orders.created_at >= ?", filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("orders.created_at <= ?", filter.EndTime)
+	}
+	return service.ApplyOrderKeywordFilter(query, userID, filter.Keyword)
+}
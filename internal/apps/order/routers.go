@@ -18,39 +18,37 @@ package order
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/oauth"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"time"
 )
 
+// TransactionListRequest 查询交易列表请求，支持分页与游标两种模式
 type TransactionListRequest struct {
-	Page      int        `json:"page" form:"page" binding:"min=1"`
-	PageSize  int        `json:"page_size" form:"page_size" binding:"min=1,max=100"`
-	Type      string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community online"`
-	Status    string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired disputing refund refused"`
-	ClientID  string     `json:"client_id" form:"client_id" binding:"omitempty"`
-	StartTime *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
-	EndTime   *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
+	util.PageRequest
+	TransactionFilter
+}
+
+// TransactionItem 交易列表中的单条交易
+type TransactionItem struct {
+	model.Order
+	AppName        string  `json:"app_name"`
+	AppHomepageURL string  `json:"app_homepage_url"`
+	AppDescription string  `json:"app_description"`
+	RedirectURI    string  `json:"redirect_uri"`
+	DisputeID      *uint64 `json:"dispute_id"`
+	PayerUsername  string  `json:"payer_username"`
+	PayeeUsername  string  `json:"payee_username"`
 }
 
 type TransactionListResponse struct {
-	Total    int64 `json:"total"`
-	Page     int   `json:"page"`
-	PageSize int   `json:"page_size"`
-	Orders   []struct {
-		model.Order
-		AppName        string  `json:"app_name"`
-		AppHomepageURL string  `json:"app_homepage_url"`
-		AppDescription string  `json:"app_description"`
-		RedirectURI    string  `json:"redirect_uri"`
-		DisputeID      *uint64 `json:"dispute_id"`
-		PayerUsername  string  `json:"payer_username"`
-		PayeeUsername  string  `json:"payee_username"`
-	} `json:"orders"`
+	util.PageResponse
+	Orders []TransactionItem `json:"orders"`
 }
 
 // ListTransactions 获取交易列表
@@ -69,71 +67,37 @@ func ListTransactions(c *gin.Context) {
 
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	baseQuery := db.DB(c.Request.Context()).Model(&model.Order{}).
-		Select("orders.*, merchant_api_keys.app_name, merchant_api_keys.app_homepage_url, merchant_api_keys.app_description, merchant_api_keys.redirect_uri, disputes.id as dispute_id, payer_user.username as payer_username, payee_user.username as payee_username").
-		Joins("LEFT JOIN merchant_api_keys ON orders.client_id = merchant_api_keys.client_id").
-		Joins("LEFT JOIN disputes ON orders.id = disputes.order_id").
-		Joins("LEFT JOIN users as payer_user ON orders.payer_user_id = payer_user.id").
-		Joins("LEFT JOIN users as payee_user ON orders.payee_user_id = payee_user.id")
-
-	clientIDHandled := false
-	if req.Type != "" {
-		orderType := model.OrderType(req.Type)
-
-		switch orderType {
-		case model.OrderTypeReceive:
-			// receive 类型：查询当前用户作为收款方的 payment 订单
-			baseQuery = baseQuery.Where("orders.type = ? AND orders.payee_user_id = ?", model.OrderTypePayment, user.ID)
-		case model.OrderTypeCommunity:
-			// community 类型：查询当前用户作为收款方的 community 订单
-			baseQuery = baseQuery.Where("orders.type = ? AND orders.payee_user_id = ?", orderType, user.ID)
-		case model.OrderTypeOnline:
-			// 不为空查询商家的 online 订单，否则按照 online 查询
-			if req.ClientID != "" {
-				baseQuery = baseQuery.Where("orders.type = ? AND orders.client_id = ?", orderType, req.ClientID)
-				clientIDHandled = true
-			} else {
-				baseQuery = baseQuery.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, user.ID, user.ID)
-			}
-		case model.OrderTypePayment, model.OrderTypeTransfer:
-			// payment、transfer 类型：查询当前用户作为付款方的订单
-			baseQuery = baseQuery.Where("orders.type = ? AND orders.payer_user_id = ?", orderType, user.ID)
-		}
-	} else {
-		baseQuery = baseQuery.Where("orders.payee_user_id = ? OR orders.payer_user_id = ?", user.ID, user.ID)
-	}
-
-	if req.Status != "" {
-		baseQuery = baseQuery.Where("orders.status = ?", model.OrderStatus(req.Status))
+	// 与用户相关的订单不早于用户注册时间，两种分页模式都以此作为 created_at 下界以便按分区裁剪
+	sinceUserCreated := func(query *gorm.DB) *gorm.DB {
+		return query.Where("orders.created_at >= ?", user.CreatedAt)
 	}
 
-	if req.ClientID != "" && !clientIDHandled {
-		baseQuery = baseQuery.Where("orders.client_id = ?", req.ClientID)
-	}
-	if req.StartTime != nil {
-		baseQuery = baseQuery.Where("orders.created_at >= ?", req.StartTime)
-	}
-	if req.EndTime != nil {
-		baseQuery = baseQuery.Where("orders.created_at <= ?", req.EndTime)
+	// 统计总数只依赖 orders 上的筛选条件，无需关联其他表
+	var total *int64
+	if req.NeedTotal() {
+		var count int64
+		countQuery := ApplyTransactionFilter(db.DB(c.Request.Context()).Model(&model.Order{}).Scopes(sinceUserCreated), user.ID, req.TransactionFilter)
+		if err := countQuery.Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		total = &count
 	}
 
-	var total int64
-	if err := baseQuery.Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	listQuery, err := req.Paginate(ApplyTransactionFilter(transactionListQuery(c).Scopes(sinceUserCreated), user.ID, req.TransactionFilter), "orders")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
-	response := &TransactionListResponse{
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
-	}
-
-	offset := (req.Page - 1) * req.PageSize
-	if err := baseQuery.Order("orders.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Orders).Error; err != nil {
+	response := &TransactionListResponse{}
+	if err := listQuery.Find(&response.Orders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	response.PageResponse = util.BuildPageResponse(&req.PageRequest, total, &response.Orders, func(item *TransactionItem) (time.Time, uint64) {
+		return item.CreatedAt, item.ID
+	})
 
 	// 转换订单类型：从收款方视角看，payment 订单应该显示为 receive
 	for i := range response.Orders {
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+)
+
+const (
+	defaultOrderArchiveAfterDays = 90
+	defaultOrderArchiveBatchSize = 1000
+)
+
+// HandleManageOrderPartitions 预建当前月份至未来 OrderPartitionPremakeMonths 个月的订单分区
+// 分区需在对应月份的数据写入兜底分区之前建好，否则建分区会失败
+func HandleManageOrderPartitions(ctx context.Context, t *asynq.Task) error {
+	months := max(config.Config.Schedule.OrderPartitionPremakeMonths, 1)
+	now := time.Now()
+
+	created, err := model.EnsureOrderPartitions(db.DB(db.WithPrimary(ctx)), now, now.AddDate(0, months, 0))
+	if err != nil {
+		return fmt.Errorf("维护订单分区失败: %w", err)
+	}
+	if created > 0 {
+		logger.InfoF(ctx, "已新建 %d 个订单分区", created)
+	}
+	return nil
+}
+
+// HandleArchiveOrders 将创建超过 OrderArchiveAfterDays 天的过期、失败、已关闭订单移入归档表
+func HandleArchiveOrders(ctx context.Context, t *asynq.Task) error {
+	days := config.Config.Schedule.OrderArchiveAfterDays
+	if days <= 0 {
+		days = defaultOrderArchiveAfterDays
+	}
+	batchSize := config.Config.Schedule.OrderArchiveBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultOrderArchiveBatchSize
+	}
+
+	count, err := service.ArchiveStaleOrders(ctx, time.Now().AddDate(0, 0, -days), batchSize)
+	if err != nil {
+		return fmt.Errorf("归档订单失败: %w", err)
+	}
+	if count > 0 {
+		logger.InfoF(ctx, "已归档 %d 个订单", count)
+	}
+	return nil
+}
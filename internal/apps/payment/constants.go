@@ -16,6 +16,8 @@ limitations under the License.
 
 package payment
 
+import "time"
+
 const (
 	APIKeyObjKey          = "payment_api_key_obj"
 	CreateOrderRequestKey = "payment_create_order_request"
@@ -27,3 +29,15 @@ const (
 	// OrderExpireKeyFormat Redis key 格式，用于订单过期监听，key中包含订单ID
 	OrderExpireKeyFormat = "payment:order:expire:%d"
 )
+
+const (
+	// OrderEventsHeartbeatInterval SSE 心跳间隔，防止代理断开空闲连接
+	OrderEventsHeartbeatInterval = 15 * time.Second
+	// OrderEventsMaxDuration 单个 SSE 连接的最长时间，到期后由客户端自动重连
+	OrderEventsMaxDuration = 30 * time.Minute
+	// OrderEventsBufferSize 单个订阅者的事件缓冲
+	OrderEventsBufferSize = 8
+)
+
+// ExpireSweepBatchSize 到期订单兜底扫描的单批数量
+const ExpireSweepBatchSize = 500
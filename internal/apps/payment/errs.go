@@ -27,4 +27,5 @@ const (
 	CannotTransferToSelf     = "不能转账给自己"
 	PayConfigNotFound        = "支付配置不存在"
 	SystemConfigValueInvalid = "系统配置 %s 的值无法转换为整数: %v"
+	OrderEventsUnavailable   = "订单状态推送暂不可用"
 )
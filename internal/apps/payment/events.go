@@ -0,0 +1,241 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// orderStatusHub 订单状态订阅中心
+// 每个实例只向 Redis 订阅一次 order:status:*，再按订单 ID 分发给本实例的 SSE 连接，
+// 状态变更无论由哪个实例发布都能送达
+type orderStatusHub struct {
+	mu          sync.RWMutex
+	subscribers map[uint64]map[chan *model.OrderStatusEvent]struct{}
+	startMu     sync.Mutex
+	started     bool
+	closing     chan struct{}
+	closeOnce   sync.Once
+}
+
+var statusHub = &orderStatusHub{
+	subscribers: make(map[uint64]map[chan *model.OrderStatusEvent]struct{}),
+	closing:     make(chan struct{}),
+}
+
+// CloseOrderEventStreams 服务关闭时结束所有 SSE 连接，避免阻塞优雅退出
+func CloseOrderEventStreams() {
+	statusHub.closeOnce.Do(func() {
+		close(statusHub.closing)
+	})
+}
+
+// start 首次订阅时启动 Redis 模式订阅，失败时下次订阅重试
+func (h *orderStatusHub) start() error {
+	h.startMu.Lock()
+	defer h.startMu.Unlock()
+
+	if h.started {
+		return nil
+	}
+	if db.Redis == nil {
+		return errors.New(OrderEventsUnavailable)
+	}
+
+	ctx := context.Background()
+	pubSub := db.Redis.PSubscribe(ctx, db.PrefixedKey(model.OrderStatusChannelPattern))
+	if _, err := pubSub.Receive(ctx); err != nil {
+		_ = pubSub.Close()
+		return err
+	}
+
+	// Channel 在连接断开后会自动重连并重新订阅
+	go func() {
+		for msg := range pubSub.Channel() {
+			var event model.OrderStatusEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logger.ErrorF(ctx, "解析订单状态事件失败: %v", err)
+				continue
+			}
+			h.dispatch(&event)
+		}
+	}()
+
+	h.started = true
+	return nil
+}
+
+// subscribe 订阅单个订单的状态变更，返回的函数用于取消订阅
+func (h *orderStatusHub) subscribe(orderID uint64) (<-chan *model.OrderStatusEvent, func(), error) {
+	if err := h.start(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *model.OrderStatusEvent, OrderEventsBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[orderID] == nil {
+		h.subscribers[orderID] = make(map[chan *model.OrderStatusEvent]struct{})
+	}
+	h.subscribers[orderID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[orderID], ch)
+		if len(h.subscribers[orderID]) == 0 {
+			delete(h.subscribers, orderID)
+		}
+		h.mu.Unlock()
+	}, nil
+}
+
+// dispatch 将事件分发给本实例订阅该订单的连接，缓冲已满的连接丢弃该事件
+func (h *orderStatusHub) dispatch(event *model.OrderStatusEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[event.OrderID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// streamOrderStatus 以 SSE 推送订单状态，先推送当前状态，订单进入终态或连接超时后结束
+func streamOrderStatus(c *gin.Context, orderID uint64) {
+	events, unsubscribe, err := statusHub.subscribe(orderID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, util.Err(OrderEventsUnavailable))
+		return
+	}
+	defer unsubscribe()
+
+	// 先订阅再读取当前状态，避免错过两者之间的变更
+	var order model.Order
+	if err := db.DB(c.Request.Context()).
+		Select("id, status, updated_at").
+		Where("id = ?", orderID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("status", &model.OrderStatusEvent{OrderID: order.ID, Status: order.Status, UpdatedAt: order.UpdatedAt})
+	c.Writer.Flush()
+	if order.Status.IsFinal() {
+		return
+	}
+
+	heartbeat := time.NewTicker(OrderEventsHeartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(OrderEventsMaxDuration)
+	defer deadline.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-statusHub.closing:
+			return false
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+			return true
+		case event := <-events:
+			c.SSEvent("status", event)
+			return !event.Status.IsFinal()
+		}
+	})
+}
+
+// StreamPaymentPageStatus 收银台订阅订单状态（SSE）
+// @Tags payment
+// @Produce text/event-stream
+// @Param order_no query string true "订单号"
+// @Success 200 {object} model.OrderStatusEvent
+// @Router /api/v1/merchant/payment/order/events [get]
+func StreamPaymentPageStatus(c *gin.Context) {
+	var req GetOrderRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	orderCtx, errCtx := ParseOrderNo(c, req.OrderNo)
+	if HandleParseOrderNoError(c, errCtx) {
+		return
+	}
+
+	streamOrderStatus(c, orderCtx.OrderID)
+}
+
+// StreamMerchantOrderStatus 商户使用 pid/key 订阅订单状态（SSE）
+// @Tags payment
+// @Produce text/event-stream
+// @Param request query QueryOrderRequest true "查询参数"
+// @Success 200 {object} model.OrderStatusEvent
+// @Router /api/v1/merchant/payment/events [get]
+func StreamMerchantOrderStatus(c *gin.Context) {
+	var req QueryOrderRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := db.DB(c.Request.Context()).Where("client_id = ? AND client_secret = ?", req.ClientID, req.ClientSecret).First(&apiKey).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, util.Err(MerchantInfoNotFound))
+		return
+	}
+
+	var orderCount int64
+	if err := db.DB(c.Request.Context()).Model(&model.Order{}).
+		Where("id = ? AND client_id = ?", req.TradeNo, apiKey.ClientID).
+		Count(&orderCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if orderCount == 0 {
+		c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+		return
+	}
+
+	streamOrderStatus(c, req.TradeNo)
+}
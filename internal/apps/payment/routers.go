@@ -17,7 +17,6 @@ limitations under the License.
 package payment
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -26,13 +25,10 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/hibiken/asynq"
 	"github.com/linux-do/pay/internal/apps/oauth"
 	"github.com/linux-do/pay/internal/common"
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/service"
-	"github.com/linux-do/pay/internal/task"
-	"github.com/linux-do/pay/internal/task/schedule"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/db"
@@ -56,8 +52,10 @@ type GetOrderRequest struct {
 
 // MerchantInfo 商户信息
 type MerchantInfo struct {
-	AppName     string `json:"app_name"`
-	RedirectURI string `json:"redirect_uri"`
+	AppName     string           `json:"app_name"`
+	RedirectURI string           `json:"redirect_uri"`
+	TrustBadge  model.TrustBadge `json:"trust_badge"`
+	DisputeRate decimal.Decimal  `json:"dispute_rate"`
 }
 
 // GetOrderResponse 查询订单响应
@@ -134,7 +132,7 @@ func CreateMerchantOrder(c *gin.Context) {
 				Type:            model.OrderTypePayment,
 				Remark:          req.Remark,
 				PaymentType:     req.PaymentType,
-				ExpiresAt:       time.Now().Add(time.Duration(expireMinutes) * time.Minute),
+				ExpiresAt:       time.Now().Add(min(time.Duration(expireMinutes)*time.Minute, model.MaxOrderLifetime)),
 			}
 			if err := tx.Create(&order).Error; err != nil {
 				return err
@@ -155,6 +153,11 @@ func CreateMerchantOrder(c *gin.Context) {
 				return fmt.Errorf("failed to set order expire key: %w", errSet)
 			}
 
+			// 到期任务持久化在任务队列中，不依赖 keyspace 通知
+			if err := service.EnqueueOrderExpire(order.ID, order.ExpiresAt); err != nil {
+				return err
+			}
+
 			payURL = fmt.Sprintf("%s?order_no=%s", config.Config.App.FrontendPayURL, url.QueryEscape(encryptString))
 			return nil
 		},
@@ -267,7 +270,11 @@ func RefundMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+	var payerUserID uint64
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(), func(tx *gorm.DB) error {
+		notifications = nil
+
 		var order model.Order
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("id = ? AND client_id = ? AND status = ? AND amount = ?", req.TradeNo, req.ClientID, model.OrderStatusSuccess, req.Amount).
@@ -282,6 +289,7 @@ func RefundMerchantOrder(c *gin.Context) {
 		if err := payerUser.GetByID(tx, order.PayerUserID); err != nil {
 			return err
 		}
+		payerUserID = payerUser.ID
 
 		var merchantUser model.User
 		if err := tx.Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
@@ -320,12 +328,37 @@ func RefundMerchantOrder(c *gin.Context) {
 			return err
 		}
 
+		if err := tx.Create(&model.OrderRefund{
+			OrderID:        order.ID,
+			ClientID:       order.ClientID,
+			Amount:         order.Amount,
+			Source:         model.RefundSourceMerchantAPI,
+			OperatorUserID: merchantUser.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := notifications.Create(tx, &model.Notification{
+			UserID:   payerUser.ID,
+			Category: model.NotificationCategoryRefund,
+			Type:     model.NotificationTypeRefundReceived,
+			Title:    "收到退款",
+			Content:  fmt.Sprintf("%s 退还了订单「%s」的款项 %s", merchantUser.Username, order.OrderName, order.Amount.StringFixed(2)),
+			OrderID:  &order.ID,
+		}); err != nil {
+			return err
+		}
+
 		return nil
 	}); err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": -1, "msg": err.Error()})
 		return
 	}
 
+	model.InvalidateUserCache(c.Request.Context(), apiKey.UserID, payerUserID)
+	model.PublishOrderStatus(c.Request.Context(), req.TradeNo, model.OrderStatusRefund)
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": 1,
 		"msg":  "退款成功",
@@ -374,12 +407,21 @@ func GetPaymentPageDetails(c *gin.Context) {
 		return
 	}
 
+	// 商户信誉尚未计算时视为新商户
+	reputation := model.MerchantReputation{TrustBadge: model.TrustBadgeNew}
+	if err := reputation.GetByUserID(db.DB(c.Request.Context()), orderCtx.MerchantUser.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
 	c.JSON(http.StatusOK, util.OK(GetOrderResponse{
 		Order:   &order,
 		FeeRate: orderCtx.MerchantPayConfig.FeeRate,
 		Merchant: MerchantInfo{
 			AppName:     merchant.AppName,
 			RedirectURI: merchant.RedirectURI,
+			TrustBadge:  reputation.TrustBadge,
+			DisputeRate: reputation.DisputeRate,
 		},
 	}))
 }
@@ -407,8 +449,11 @@ func PayMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	var notifications service.PendingNotifications
+	if err := db.Transaction(c.Request.Context(),
 		func(tx *gorm.DB) error {
+			notifications = nil
+
 			var order model.Order
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND status = ?", orderCtx.OrderID, model.OrderStatusPending).
@@ -419,8 +464,8 @@ func PayMerchantOrder(c *gin.Context) {
 				return err
 			}
 
-			// 检查订单是否过期
-			if order.ExpiresAt.Before(time.Now()) {
+			// 检查订单是否过期，创建超过最长有效期的订单同样视为过期
+			if order.ExpiresAt.Before(time.Now()) || order.CreatedAt.Add(model.MaxOrderLifetime).Before(time.Now()) {
 				return errors.New(OrderExpired)
 			}
 
@@ -430,7 +475,7 @@ func PayMerchantOrder(c *gin.Context) {
 			}
 
 			// 计算手续费
-			_, merchantAmount, feePercent := service.CalculateFee(order.Amount, orderCtx.MerchantPayConfig.FeeRate)
+			fee, merchantAmount, feePercent := service.CalculateFee(order.Amount, orderCtx.MerchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
 
 			// 更新订单状态和备注
@@ -440,6 +485,7 @@ func PayMerchantOrder(c *gin.Context) {
 				order.Remark = feeRemark
 			}
 			order.Status = model.OrderStatusSuccess
+			order.Fee = fee
 			order.PayerUserID = orderCtx.CurrentUser.ID
 			order.TradeTime = time.Now()
 			if err := tx.Save(&order).Error; err != nil {
@@ -457,26 +503,24 @@ func PayMerchantOrder(c *gin.Context) {
 				return err
 			}
 
+			if err := notifications.Create(tx, &model.Notification{
+				UserID:   orderCtx.MerchantUser.ID,
+				Category: model.NotificationCategoryPayment,
+				Type:     model.NotificationTypePaymentReceived,
+				Title:    "收到付款",
+				Content:  fmt.Sprintf("%s 支付了订单「%s」，金额 %s", orderCtx.CurrentUser.Username, order.OrderName, order.Amount.StringFixed(2)),
+				OrderID:  &order.ID,
+			}); err != nil {
+				return err
+			}
+
 			expireKey := db.PrefixedKey(fmt.Sprintf(OrderExpireKeyFormat, order.ID))
 			if err := db.Redis.Del(c.Request.Context(), expireKey).Err(); err != nil {
 				log.Printf("[Payment] 删除订单过期key失败: order_id=%d, error=%v", order.ID, err)
 			}
 
 			// 下发商户回调任务
-			notifyPayload, _ := json.Marshal(map[string]interface{}{
-				"order_id":  order.ID,
-				"client_id": order.ClientID,
-			})
-			if _, errTask := schedule.AsynqClient.Enqueue(
-				asynq.NewTask(task.MerchantPaymentNotifyTask, notifyPayload),
-				asynq.Queue(task.QueueWebhook),
-				asynq.MaxRetry(5),
-				asynq.Timeout(30*time.Second),
-			); errTask != nil {
-				return fmt.Errorf("下发商户回调任务失败: %w", errTask)
-			}
-
-			return nil
+			return service.EnqueueMerchantNotify(order.ID, order.ClientID)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -488,12 +532,18 @@ func PayMerchantOrder(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(OrderExpired))
 		} else if errMsg == common.DailyLimitExceeded {
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		} else if errMsg == common.Busy {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	model.InvalidateUserCache(c.Request.Context(), orderCtx.CurrentUser.ID, orderCtx.MerchantUser.ID)
+	model.PublishOrderStatus(c.Request.Context(), orderCtx.OrderID, model.OrderStatusSuccess)
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -533,7 +583,8 @@ func Transfer(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).Transaction(
+	var notifications service.PendingNotifications
+	if err := db.TransactionOnce(c.Request.Context(),
 		func(tx *gorm.DB) error {
 			// 验证收款人是否存在且用户名匹配
 			var recipient model.User
@@ -592,12 +643,30 @@ func Transfer(c *gin.Context) {
 				return err
 			}
 
+			if err := notifications.Create(tx, &model.Notification{
+				UserID:   recipient.ID,
+				Category: model.NotificationCategoryTransfer,
+				Type:     model.NotificationTypeTransferReceived,
+				Title:    "收到转账",
+				Content:  fmt.Sprintf("%s 向你转账 %s", payer.Username, req.Amount.StringFixed(2)),
+				OrderID:  &order.ID,
+			}); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	); err != nil {
+		if errors.Is(err, db.ErrBusy) {
+			c.JSON(http.StatusConflict, util.Err(common.Busy))
+			return
+		}
 		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
+	model.InvalidateUserCache(c.Request.Context(), currentUser.ID, req.RecipientID)
+	notifications.Dispatch(c.Request.Context())
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
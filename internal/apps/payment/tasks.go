@@ -32,8 +32,10 @@ import (
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/util"
 	"gorm.io/gorm"
+	"time"
 )
 
 // HandleMerchantPaymentNotify 处理商户支付回调任务
@@ -48,7 +50,7 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("解析任务参数失败: %w", err)
 	}
 
-	// 查询订单信息
+	// 查询订单信息，易支付兼容的 notify_url 只通知支付成功的订单，多数接入方验签后即视为已支付
 	var order model.Order
 	if err := db.DB(ctx).Where("id = ? AND status = ?", payload.OrderID, model.OrderStatusSuccess).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -79,8 +81,29 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 
 	callbackParams["sign"] = GenerateSignature(callbackParams, apiKey.ClientSecret)
 
-	if err := sendCallbackRequest(ctx, apiKey.NotifyURL, callbackParams); err != nil {
-		retried, _ := asynq.GetRetryCount(ctx)
+	retried, _ := asynq.GetRetryCount(ctx)
+	startedAt := time.Now()
+	statusCode, respBody, err := sendCallbackRequest(ctx, apiKey.NotifyURL, callbackParams)
+
+	// 记录每次投递结果，供商户在订单详情中查看
+	notifyLog := model.MerchantNotifyLog{
+		OrderID:    order.ID,
+		ClientID:   payload.ClientID,
+		NotifyURL:  truncate(apiKey.NotifyURL, 255),
+		Attempt:    retried + 1,
+		Success:    err == nil,
+		StatusCode: statusCode,
+		Response:   truncate(respBody, 255),
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		notifyLog.ErrorMessage = truncate(err.Error(), 255)
+	}
+	if errLog := db.DB(ctx).Create(&notifyLog).Error; errLog != nil {
+		logger.ErrorF(ctx, "记录商户回调日志失败: 订单[ID:%d] 错误: %v", order.ID, errLog)
+	}
+
+	if err != nil {
 		maxRetry := 5
 
 		logger.ErrorF(ctx, "商户回调失败: 订单[ID:%d] 重试次数[%d/%d] 错误: %v",
@@ -98,8 +121,44 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 	return nil
 }
 
-// sendCallbackRequest 发送HTTP回调请求
-func sendCallbackRequest(ctx context.Context, callbackURL string, params map[string]string) error {
+// HandleExpireOrder 订单到期时将待支付订单置为过期
+func HandleExpireOrder(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		OrderID uint64 `json:"order_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	if _, err := service.ExpireOrder(ctx, payload.OrderID); err != nil {
+		return fmt.Errorf("过期订单[ID:%d]失败: %w", payload.OrderID, err)
+	}
+	return nil
+}
+
+// HandleSweepExpiredOrders 兜底扫描已到期但仍为待支付的订单
+func HandleSweepExpiredOrders(ctx context.Context, t *asynq.Task) error {
+	count, err := service.ExpireDueOrders(ctx, ExpireSweepBatchSize)
+	if err != nil {
+		return fmt.Errorf("扫描到期订单失败: %w", err)
+	}
+	if count > 0 {
+		logger.InfoF(ctx, "兜底扫描已过期 %d 个订单", count)
+	}
+	return nil
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
+
+// sendCallbackRequest 发送HTTP回调请求，返回状态码和响应内容
+func sendCallbackRequest(ctx context.Context, callbackURL string, params map[string]string) (int, string, error) {
 	vals := url.Values{}
 	for k, v := range params {
 		vals.Add(k, v)
@@ -118,24 +177,24 @@ func sendCallbackRequest(ctx context.Context, callbackURL string, params map[str
 
 	resp, err := util.Request(ctx, http.MethodGet, targetURL, nil, headers, nil)
 	if err != nil {
-		return err
+		return 0, "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("回调返回异常状态码: %d", resp.StatusCode)
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("读取响应失败: %w", err)
 	}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("读取响应失败: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, string(respBody), fmt.Errorf("回调返回异常状态码: %d", resp.StatusCode)
 	}
 
 	responseText := strings.TrimSpace(strings.ToLower(string(respBody)))
 	if responseText != "success" {
-		return fmt.Errorf("回调返回非成功响应: %s", string(respBody))
+		return resp.StatusCode, string(respBody), fmt.Errorf("回调返回非成功响应: %s", string(respBody))
 	}
 
 	logger.InfoF(ctx, "商户回调请求成功: URL[%s] 响应[%s]", callbackURL, string(respBody))
-	return nil
+	return resp.StatusCode, string(respBody), nil
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/linux-do/pay/internal/channel"
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+)
+
+// listPreferences 返回用户全部分类的通知偏好，未设置的分类默认开启
+func listPreferences(tx *gorm.DB, userID uint64) ([]model.NotificationPreference, error) {
+	var saved []model.NotificationPreference
+	if err := tx.Where("user_id = ?", userID).Find(&saved).Error; err != nil {
+		return nil, err
+	}
+
+	savedMap := make(map[model.NotificationCategory]model.NotificationPreference, len(saved))
+	for _, p := range saved {
+		savedMap[p.Category] = p
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(model.NotificationCategories))
+	for _, category := range model.NotificationCategories {
+		if p, ok := savedMap[category]; ok {
+			preferences = append(preferences, p)
+			continue
+		}
+		preferences = append(preferences, model.NotificationPreference{UserID: userID, Category: category, Enabled: true})
+	}
+	return preferences, nil
+}
+
+// newMessage 将站内通知转换为渠道消息
+func newMessage(notification *model.Notification) *channel.Message {
+	return &channel.Message{
+		ID:        notification.ID,
+		Category:  notification.Category,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Content:   notification.Content,
+		OrderID:   notification.OrderID,
+		DisputeID: notification.DisputeID,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+// deliver 通过用户配置的渠道发送消息
+func deliver(ctx context.Context, userChannel *model.UserNotificationChannel, user *model.User, msg *channel.Message) error {
+	ch, err := channel.Get(userChannel.Channel)
+	if err != nil {
+		return err
+	}
+	return ch.Send(ctx, &channel.Destination{
+		Username: user.Username,
+		Target:   userChannel.Target,
+		Secret:   userChannel.Secret,
+	}, msg)
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -0,0 +1,432 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/channel"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListNotificationsRequest 查询通知列表请求
+type ListNotificationsRequest struct {
+	util.PageRequest
+	Category string `json:"category" form:"category" binding:"omitempty,oneof=payment transfer dispute refund order"`
+	Unread   bool   `json:"unread" form:"unread"`
+}
+
+// ListNotificationsResponse 查询通知列表响应
+type ListNotificationsResponse struct {
+	util.PageResponse
+	Notifications []model.Notification `json:"notifications"`
+}
+
+// UnreadCountResponse 未读通知数量响应
+type UnreadCountResponse struct {
+	Total      int64                                `json:"total"`
+	Categories map[model.NotificationCategory]int64 `json:"categories"`
+}
+
+// MarkReadRequest 标记已读请求，ids 为空时标记全部（可按分类过滤）
+type MarkReadRequest struct {
+	IDs      []uint64 `json:"ids" binding:"omitempty,max=100,dive,min=1"`
+	Category string   `json:"category" binding:"omitempty,oneof=payment transfer dispute refund order"`
+}
+
+// MarkReadResponse 标记已读响应
+type MarkReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// PreferenceItem 单个分类的通知偏好
+type PreferenceItem struct {
+	Category string `json:"category" binding:"required,oneof=payment transfer dispute refund order"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// UpdatePreferencesRequest 更新通知偏好请求
+type UpdatePreferencesRequest struct {
+	Preferences []PreferenceItem `json:"preferences" binding:"required,min=1,max=10,dive"`
+}
+
+// ListNotifications 获取当前用户的通知列表
+// @Tags user
+// @Produce json
+// @Param request query ListNotificationsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notifications [get]
+func ListNotifications(c *gin.Context) {
+	var req ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.Notification{}).Where("user_id = ?", user.ID)
+	if req.Category != "" {
+		baseQuery = baseQuery.Where("category = ?", model.NotificationCategory(req.Category))
+	}
+	if req.Unread {
+		baseQuery = baseQuery.Where("read_at IS NULL")
+	}
+
+	var total *int64
+	if req.NeedTotal() {
+		var count int64
+		if err := baseQuery.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		total = &count
+	}
+
+	listQuery, err := req.Paginate(baseQuery.Session(&gorm.Session{}), "notifications")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListNotificationsResponse{}
+	if err := listQuery.Find(&response.Notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	response.PageResponse = util.BuildPageResponse(&req.PageRequest, total, &response.Notifications, func(n *model.Notification) (time.Time, uint64) {
+		return n.CreatedAt, n.ID
+	})
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetUnreadCount 获取当前用户的未读通知数量
+// @Tags user
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notifications/unread-count [get]
+func GetUnreadCount(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var rows []struct {
+		Category model.NotificationCategory
+		Count    int64
+	}
+	if err := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Select("category, COUNT(*) AS count").
+		Where("user_id = ? AND read_at IS NULL", user.ID).
+		Group("category").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &UnreadCountResponse{Categories: make(map[model.NotificationCategory]int64, len(model.NotificationCategories))}
+	for _, category := range model.NotificationCategories {
+		response.Categories[category] = 0
+	}
+	for _, row := range rows {
+		response.Categories[row.Category] = row.Count
+		response.Total += row.Count
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// MarkRead 标记通知为已读
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body MarkReadRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notifications/read [post]
+func MarkRead(c *gin.Context) {
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	query := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	if req.Category != "" {
+		query = query.Where("category = ?", model.NotificationCategory(req.Category))
+	}
+
+	result := query.UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(MarkReadResponse{Updated: result.RowsAffected}))
+}
+
+// GetPreferences 获取当前用户各分类的通知偏好
+// @Tags user
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notifications/preferences [get]
+func GetPreferences(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	preferences, err := listPreferences(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(preferences))
+}
+
+// UpdatePreferences 更新当前用户的通知偏好
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body UpdatePreferencesRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notifications/preferences [put]
+func UpdatePreferences(c *gin.Context) {
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	rows := make([]model.NotificationPreference, 0, len(req.Preferences))
+	for _, item := range req.Preferences {
+		rows = append(rows, model.NotificationPreference{
+			UserID:   user.ID,
+			Category: model.NotificationCategory(item.Category),
+			Enabled:  item.Enabled,
+		})
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).
+		Create(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	preferences, err := listPreferences(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(preferences))
+}
+
+// ChannelPathRequest 渠道路径参数
+type ChannelPathRequest struct {
+	Channel string `uri:"channel" binding:"required,oneof=email webhook discourse"`
+}
+
+// UpsertChannelRequest 配置站外通知渠道请求，secret 为空指针时保留原密钥
+type UpsertChannelRequest struct {
+	Target     string   `json:"target" binding:"max=255"`
+	Secret     *string  `json:"secret" binding:"omitempty,max=128"`
+	Enabled    bool     `json:"enabled"`
+	Categories []string `json:"categories" binding:"omitempty,max=10,dive,oneof=payment transfer dispute refund order"`
+}
+
+// ListChannelsResponse 站外通知渠道列表响应
+type ListChannelsResponse struct {
+	Available []model.NotificationChannelType `json:"available"`
+	Channels  []model.UserNotificationChannel `json:"channels"`
+}
+
+// ListChannels 获取当前用户的站外通知渠道配置
+// @Tags user
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notification-channels [get]
+func ListChannels(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	response := &ListChannelsResponse{Available: channel.Available()}
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ?", user.ID).
+		Order("id ASC").
+		Find(&response.Channels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// UpsertChannel 新增或更新站外通知渠道
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param channel path string true "渠道 email/webhook/discourse"
+// @Param request body UpsertChannelRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notification-channels/{channel} [put]
+func UpsertChannel(c *gin.Context) {
+	var path ChannelPathRequest
+	if err := c.ShouldBindUri(&path); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	var req UpsertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	channelType := model.NotificationChannelType(path.Channel)
+	ch, err := channel.Get(channelType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(ChannelUnavailable))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := ch.Validate(&channel.Destination{Username: user.Username, Target: req.Target}); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	categories := make(model.NotificationCategoryList, 0, len(req.Categories))
+	for _, category := range req.Categories {
+		categories = append(categories, model.NotificationCategory(category))
+	}
+
+	userChannel := model.UserNotificationChannel{
+		UserID:     user.ID,
+		Channel:    channelType,
+		Target:     req.Target,
+		Enabled:    req.Enabled,
+		Categories: categories,
+	}
+	updateColumns := []string{"target", "enabled", "categories", "updated_at"}
+	if req.Secret != nil {
+		userChannel.Secret = *req.Secret
+		updateColumns = append(updateColumns, "secret")
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns(updateColumns),
+		}).
+		Create(&userChannel).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ? AND channel = ?", user.ID, channelType).
+		First(&userChannel).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(userChannel))
+}
+
+// DeleteChannel 删除站外通知渠道
+// @Tags user
+// @Produce json
+// @Param channel path string true "渠道 email/webhook/discourse"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notification-channels/{channel} [delete]
+func DeleteChannel(c *gin.Context) {
+	var path ChannelPathRequest
+	if err := c.ShouldBindUri(&path); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Where("user_id = ? AND channel = ?", user.ID, model.NotificationChannelType(path.Channel)).
+		Delete(&model.UserNotificationChannel{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(ChannelNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// TestChannel 立即向渠道发送一条测试通知
+// @Tags user
+// @Produce json
+// @Param channel path string true "渠道 email/webhook/discourse"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/notification-channels/{channel}/test [post]
+func TestChannel(c *gin.Context) {
+	var path ChannelPathRequest
+	if err := c.ShouldBindUri(&path); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var userChannel model.UserNotificationChannel
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ? AND channel = ?", user.ID, model.NotificationChannelType(path.Channel)).
+		First(&userChannel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ChannelNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := deliver(c.Request.Context(), &userChannel, user, &channel.Message{
+		Title:     "测试通知",
+		Content:   "这是一条测试通知，收到说明通知渠道配置正确。",
+		CreatedAt: time.Now(),
+	}); err != nil {
+		c.JSON(http.StatusBadGateway, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
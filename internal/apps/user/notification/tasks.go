@@ -0,0 +1,143 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/channel"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+)
+
+// HandleDispatchNotification 按用户的渠道配置为通知下发各渠道投递任务
+func HandleDispatchNotification(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		NotificationID uint64 `json:"notification_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	// 通知刚提交，从主库读取避免只读副本尚未同步时误判为不存在
+	var notification model.Notification
+	if err := db.DB(db.WithPrimary(ctx)).Where("id = ?", payload.NotificationID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.InfoF(ctx, "通知[ID:%d]不存在，跳过分发", payload.NotificationID)
+			return nil
+		}
+		return err
+	}
+
+	var channels []model.UserNotificationChannel
+	if err := db.DB(ctx).
+		Where("user_id = ? AND enabled = ?", notification.UserID, true).
+		Find(&channels).Error; err != nil {
+		return err
+	}
+
+	for _, userChannel := range channels {
+		if !userChannel.Accepts(notification.Category) {
+			continue
+		}
+		if _, err := channel.Get(userChannel.Channel); err != nil {
+			continue
+		}
+
+		deliverPayload, _ := json.Marshal(map[string]interface{}{
+			"notification_id": notification.ID,
+			"channel_id":      userChannel.ID,
+		})
+		if _, err := schedule.AsynqClient.Enqueue(
+			asynq.NewTask(task.NotificationDeliverTask, deliverPayload),
+			asynq.Queue(task.QueueNotify),
+			asynq.MaxRetry(config.Config.Notify.MaxRetry),
+			asynq.Timeout(time.Minute),
+		); err != nil {
+			return fmt.Errorf("下发通知[ID:%d]渠道[%s]投递任务失败: %w", notification.ID, userChannel.Channel, err)
+		}
+	}
+
+	return nil
+}
+
+// HandleDeliverNotification 将通知投递到单个站外渠道，失败时由队列重试
+func HandleDeliverNotification(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		NotificationID uint64 `json:"notification_id"`
+		ChannelID      uint64 `json:"channel_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var notification model.Notification
+	if err := db.DB(db.WithPrimary(ctx)).Where("id = ?", payload.NotificationID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var userChannel model.UserNotificationChannel
+	if err := db.DB(ctx).
+		Where("id = ? AND user_id = ?", payload.ChannelID, notification.UserID).
+		First(&userChannel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !userChannel.Accepts(notification.Category) {
+		logger.InfoF(ctx, "用户已关闭渠道[ID:%d]，跳过通知[ID:%d]", userChannel.ID, notification.ID)
+		return nil
+	}
+
+	var user model.User
+	if err := user.GetByID(db.DB(ctx), notification.UserID); err != nil {
+		return err
+	}
+
+	sendErr := deliver(ctx, &userChannel, &user, newMessage(&notification))
+
+	updates := map[string]interface{}{"last_error": ""}
+	if sendErr != nil {
+		updates["last_error"] = truncate(sendErr.Error(), 255)
+	} else {
+		updates["last_delivered_at"] = time.Now()
+	}
+	if err := db.DB(ctx).Model(&model.UserNotificationChannel{}).
+		Where("id = ?", userChannel.ID).
+		UpdateColumns(updates).Error; err != nil {
+		logger.ErrorF(ctx, "更新渠道[ID:%d]投递状态失败: %v", userChannel.ID, err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("投递通知[ID:%d]到渠道[%s]失败: %w", notification.ID, userChannel.Channel, sendErr)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/audit"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
@@ -60,6 +61,11 @@ func UpdatePayKey(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
+	model.InvalidateUserCache(c.Request.Context(), user.ID)
+
+	audit.SetTarget(c, audit.TargetUser, user.ID)
+	audit.SetBefore(c, map[string]string{"pay_key": user.PayKey})
+	audit.SetAfter(c, map[string]string{"pay_key": encryptedPayKey})
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
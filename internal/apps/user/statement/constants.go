@@ -0,0 +1,45 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statement
+
+import "github.com/linux-do/pay/internal/model"
+
+const (
+	// MonthLayout 月份格式
+	MonthLayout = "2006-01"
+	// batchSize 每个生成任务处理的用户数
+	batchSize = 200
+)
+
+// ledgerStatuses 计入月结单的订单状态，已退款订单另有冲正行
+var ledgerStatuses = []model.OrderStatus{
+	model.OrderStatusSuccess,
+	model.OrderStatusDisputing,
+	model.OrderStatusRefused,
+	model.OrderStatusRefund,
+}
+
+// lineTypeLabels 明细行类型的展示名称
+var lineTypeLabels = map[string]string{
+	string(model.OrderTypeReceive):    "收款",
+	string(model.OrderTypePayment):    "付款",
+	string(model.OrderTypeTransfer):   "转账",
+	string(model.OrderTypeCommunity):  "社区积分",
+	string(model.OrderTypeOnline):     "在线支付",
+	string(model.OrderTypeAdjustment): "余额调整",
+	model.StatementLineTypeRefund:     "退款",
+}
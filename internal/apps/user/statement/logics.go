@@ -0,0 +1,248 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statement
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+)
+
+// statementLocation 月结单的自然月所在时区，与定时任务调度时区一致，保证月切与生成任务的触发时间对齐
+var statementLocation = loadStatementLocation()
+
+func loadStatementLocation() *time.Location {
+	location, err := schedule.Location()
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ParseMonth 解析月份，返回该自然月的起止时间 [start, end)
+func ParseMonth(month string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(MonthLayout, month, statementLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(InvalidMonth)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// previousMonth 上一个自然月
+func previousMonth(now time.Time) string {
+	now = now.In(statementLocation)
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, statementLocation).AddDate(0, -1, 0).Format(MonthLayout)
+}
+
+type lineKey struct {
+	Type      string
+	Direction model.StatementDirection
+}
+
+// userFlows 单个用户在一段时间内的资金流水汇总
+type userFlows struct {
+	lines map[lineKey]*model.StatementLine
+}
+
+func (f *userFlows) add(lineType string, direction model.StatementDirection, count int64, amount, fee decimal.Decimal) {
+	key := lineKey{Type: lineType, Direction: direction}
+	line, ok := f.lines[key]
+	if !ok {
+		line = &model.StatementLine{Type: lineType, Direction: direction}
+		f.lines[key] = line
+	}
+	line.Count += count
+	line.Amount = line.Amount.Add(amount)
+	line.Fee = line.Fee.Add(fee)
+}
+
+// Totals 收入、支出和手续费合计，手续费从收入中扣除
+func (f *userFlows) Totals() (credit, debit, fee decimal.Decimal) {
+	for _, line := range f.lines {
+		if line.Direction == model.StatementDirectionCredit {
+			credit = credit.Add(line.Amount)
+			fee = fee.Add(line.Fee)
+		} else {
+			debit = debit.Add(line.Amount)
+		}
+	}
+	return
+}
+
+// Net 余额净变动
+func (f *userFlows) Net() decimal.Decimal {
+	credit, debit, fee := f.Totals()
+	return credit.Sub(fee).Sub(debit)
+}
+
+// Lines 按类型和方向排序后的明细
+func (f *userFlows) Lines() model.StatementLines {
+	lines := make(model.StatementLines, 0, len(f.lines))
+	for _, line := range f.lines {
+		lines = append(lines, *line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Direction != lines[j].Direction {
+			return lines[i].Direction == model.StatementDirectionCredit
+		}
+		return lines[i].Type < lines[j].Type
+	})
+	return lines
+}
+
+type flowRow struct {
+	UserID uint64
+	Type   string
+	Count  int64
+	Amount decimal.Decimal
+	Fee    decimal.Decimal
+}
+
+// aggregateFlows 按交易时间汇总一批用户在 [start, end) 内的资金流水
+// 已退款订单先按原方向计入，再以 refund 行冲正：付款方收回全额，收款方退回全额
+func aggregateFlows(ctx context.Context, userIDs []uint64, start, end time.Time) (map[uint64]*userFlows, error) {
+	readDB := db.DB(db.WithReplica(ctx))
+
+	result := make(map[uint64]*userFlows, len(userIDs))
+	getFlows := func(userID uint64) *userFlows {
+		if f, ok := result[userID]; ok {
+			return f
+		}
+		f := &userFlows{lines: make(map[lineKey]*model.StatementLine)}
+		result[userID] = f
+		return f
+	}
+
+	queries := []struct {
+		userColumn string
+		statuses   []model.OrderStatus
+		direction  model.StatementDirection
+		refund     bool
+	}{
+		{"payee_user_id", ledgerStatuses, model.StatementDirectionCredit, false},
+		{"payer_user_id", ledgerStatuses, model.StatementDirectionDebit, false},
+		{"payer_user_id", []model.OrderStatus{model.OrderStatusRefund}, model.StatementDirectionCredit, true},
+		{"payee_user_id", []model.OrderStatus{model.OrderStatusRefund}, model.StatementDirectionDebit, true},
+	}
+
+	for _, q := range queries {
+		var rows []flowRow
+		if err := readDB.Model(&model.Order{}).
+			Select(q.userColumn+" AS user_id, type, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount, COALESCE(SUM(fee), 0) AS fee").
+			Where(q.userColumn+" IN ? AND status IN ? AND trade_time >= ? AND trade_time < ?", userIDs, q.statuses, start, end).
+			Group("1, 2").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			lineType := row.Type
+			fee := decimal.Zero
+			switch {
+			case q.refund:
+				lineType = model.StatementLineTypeRefund
+			case q.direction == model.StatementDirectionCredit:
+				// 收款方视角的 payment 订单显示为 receive，商户实收金额需扣除手续费
+				if lineType == string(model.OrderTypePayment) {
+					lineType = string(model.OrderTypeReceive)
+				}
+				fee = row.Fee
+			}
+			getFlows(row.UserID).add(lineType, q.direction, row.Count, row.Amount, fee)
+		}
+	}
+
+	return result, nil
+}
+
+// GenerateStatements 为一批用户生成指定月份的月结单快照
+// 期末余额由当前余额减去期末之后的净变动倒推，期初余额再减去本月净变动
+func GenerateStatements(ctx context.Context, month string, userIDs []uint64) (int, error) {
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	if end.After(now) {
+		end = now
+	}
+
+	var users []model.User
+	if err := db.DB(ctx).Select("id, available_balance").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	periodFlows, err := aggregateFlows(ctx, userIDs, start, end)
+	if err != nil {
+		return 0, err
+	}
+	laterFlows, err := aggregateFlows(ctx, userIDs, end, now)
+	if err != nil {
+		return 0, err
+	}
+
+	statements := make([]model.MonthlyStatement, 0, len(users))
+	for _, user := range users {
+		closing := user.AvailableBalance
+		if later, ok := laterFlows[user.ID]; ok {
+			closing = closing.Sub(later.Net())
+		}
+
+		statement := model.MonthlyStatement{
+			UserID:         user.ID,
+			Month:          month,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			OpeningBalance: closing,
+			ClosingBalance: closing,
+			Lines:          model.StatementLines{},
+		}
+		if flows, ok := periodFlows[user.ID]; ok {
+			statement.TotalCredit, statement.TotalDebit, statement.TotalFee = flows.Totals()
+			statement.OpeningBalance = closing.Sub(flows.Net())
+			statement.Lines = flows.Lines()
+		} else if closing.IsZero() {
+			// 本月无流水且余额为零的用户不生成月结单
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	if len(statements) == 0 {
+		return 0, nil
+	}
+
+	if err := db.DB(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "month"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"period_start", "period_end", "opening_balance", "total_credit", "total_debit",
+				"total_fee", "closing_balance", "lines", "updated_at",
+			}),
+		}).
+		CreateInBatches(statements, batchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(statements), nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statement
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ListStatementsRequest 查询月结单列表请求
+type ListStatementsRequest struct {
+	Page     int `json:"page" form:"page" binding:"min=1"`
+	PageSize int `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+}
+
+// ListStatementsResponse 查询月结单列表响应，列表不含明细
+type ListStatementsResponse struct {
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+	Statements []model.MonthlyStatement `json:"statements"`
+}
+
+// GetStatementRequest 查询单个月结单请求
+type GetStatementRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json html"`
+}
+
+// ListStatements 获取当前用户的月结单列表
+// @Tags user
+// @Produce json
+// @Param request query ListStatementsRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/statements [get]
+func ListStatements(c *gin.Context) {
+	var req ListStatementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.MonthlyStatement{}).Where("user_id = ?", user.ID)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListStatementsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Omit("lines").Order("month DESC").Offset(offset).Limit(req.PageSize).Find(&response.Statements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetStatement 获取指定月份的月结单，format=html 时返回可打印页面
+// @Tags user
+// @Produce json,html
+// @Param month path string true "月份 YYYY-MM"
+// @Param request query GetStatementRequest false "request query"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/statements/{month} [get]
+func GetStatement(c *gin.Context) {
+	var req GetStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	month := c.Param("month")
+	if _, _, err := ParseMonth(month); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var statement model.MonthlyStatement
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ? AND month = ?", user.ID, month).
+		First(&statement).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(StatementNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if req.Format != "html" {
+		c.JSON(http.StatusOK, util.OK(statement))
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := statementTemplate.Execute(c.Writer, &statementView{
+		AppName:   config.Config.App.AppName,
+		Username:  user.Username,
+		Statement: &statement,
+	}); err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+)
+
+// GeneratePayload 月结单生成任务参数，未指定月份时生成上一个自然月
+type GeneratePayload struct {
+	Month string `json:"month"`
+}
+
+// BatchPayload 单批用户的月结单生成任务参数
+type BatchPayload struct {
+	Month   string   `json:"month"`
+	UserIDs []uint64 `json:"user_ids"`
+}
+
+// HandleGenerateMonthlyStatements 按批次下发月结单生成任务
+func HandleGenerateMonthlyStatements(ctx context.Context, t *asynq.Task) error {
+	var payload GeneratePayload
+	if len(t.Payload()) > 0 {
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			return fmt.Errorf("解析任务参数失败: %w", err)
+		}
+	}
+	if payload.Month == "" {
+		payload.Month = previousMonth(time.Now())
+	}
+
+	_, end, err := ParseMonth(payload.Month)
+	if err != nil {
+		return err
+	}
+
+	lastID := uint64(0)
+	batches := 0
+	for {
+		var userIDs []uint64
+		if err := db.DB(ctx).Model(&model.User{}).
+			Where("id > ? AND created_at < ?", lastID, end).
+			Order("id ASC").
+			Limit(batchSize).
+			Pluck("id", &userIDs).Error; err != nil {
+			logger.ErrorF(ctx, "查询用户失败: %v", err)
+			return err
+		}
+
+		if len(userIDs) == 0 {
+			break
+		}
+
+		batchPayload, _ := json.Marshal(BatchPayload{Month: payload.Month, UserIDs: userIDs})
+		if _, errTask := schedule.AsynqClient.Enqueue(
+			asynq.NewTask(task.GenerateStatementBatchTask, batchPayload),
+			asynq.MaxRetry(3),
+		); errTask != nil {
+			logger.ErrorF(ctx, "下发月结单[%s]生成任务失败: %v", payload.Month, errTask)
+			return errTask
+		}
+		batches++
+
+		lastID = userIDs[len(userIDs)-1]
+	}
+
+	logger.InfoF(ctx, "已下发月结单[%s]生成任务 %d 批", payload.Month, batches)
+	return nil
+}
+
+// HandleGenerateStatementBatch 生成单批用户的月结单
+func HandleGenerateStatementBatch(ctx context.Context, t *asynq.Task) error {
+	var payload BatchPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+	if len(payload.UserIDs) == 0 {
+		return nil
+	}
+
+	count, err := GenerateStatements(ctx, payload.Month, payload.UserIDs)
+	if err != nil {
+		return fmt.Errorf("生成月结单[%s]失败: %w", payload.Month, err)
+	}
+
+	logger.InfoF(ctx, "已生成月结单[%s] %d 份", payload.Month, count)
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statement
+
+import (
+	"html/template"
+
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// statementView 可打印月结单的渲染数据
+type statementView struct {
+	AppName   string
+	Username  string
+	Statement *model.MonthlyStatement
+}
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"money": func(d decimal.Decimal) string {
+		return d.StringFixed(2)
+	},
+	"lineType": func(t string) string {
+		if label, ok := lineTypeLabels[t]; ok {
+			return label
+		}
+		return t
+	},
+	"isCredit": func(d model.StatementDirection) bool {
+		return d == model.StatementDirectionCredit
+	},
+	"date": func(s *model.MonthlyStatement) string {
+		return s.PeriodStart.In(statementLocation).Format("2006-01-02") + " ~ " +
+			s.PeriodEnd.In(statementLocation).AddDate(0, 0, -1).Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.AppName}} 月结单 {{.Statement.Month}}</title>
+<style>
+body { font-family: -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; color: #222; max-width: 760px; margin: 32px auto; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+.meta { color: #666; font-size: 13px; margin-bottom: 24px; }
+table { width: 100%; border-collapse: collapse; margin-bottom: 24px; }
+th, td { border-bottom: 1px solid #ddd; padding: 8px; text-align: left; font-size: 14px; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+.summary td { font-weight: 600; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.AppName}} 月结单</h1>
+<div class="meta">用户：{{.Username}}　账期：{{date .Statement}}　生成时间：{{.Statement.UpdatedAt.Format "2006-01-02 15:04:05"}}</div>
+<table class="summary">
+<tr><td>期初余额</td><td class="num">{{money .Statement.OpeningBalance}}</td></tr>
+<tr><td>收入合计</td><td class="num">+{{money .Statement.TotalCredit}}</td></tr>
+<tr><td>手续费合计</td><td class="num">-{{money .Statement.TotalFee}}</td></tr>
+<tr><td>支出合计</td><td class="num">-{{money .Statement.TotalDebit}}</td></tr>
+<tr><td>期末余额</td><td class="num">{{money .Statement.ClosingBalance}}</td></tr>
+</table>
+<table>
+<thead><tr><th>类型</th><th>收支</th><th class="num">笔数</th><th class="num">金额</th><th class="num">手续费</th></tr></thead>
+<tbody>
+{{range .Statement.Lines}}<tr><td>{{lineType .Type}}</td><td>{{if isCredit .Direction}}收入{{else}}支出{{end}}</td><td class="num">{{.Count}}</td><td class="num">{{money .Amount}}</td><td class="num">{{money .Fee}}</td></tr>
+{{else}}<tr><td colspan="5">本月无交易</td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
@@ -115,6 +115,7 @@ func HandleUpdateSingleUserGamificationScore(ctx context.Context, t *asynq.Task)
 		}).Error; err != nil {
 			return fmt.Errorf("初始化用户[%s]社区积分失败: %w", user.Username, err)
 		}
+		model.InvalidateUserCache(ctx, user.ID)
 		logger.InfoF(ctx, "用户[%s]首次同步社区积分: %s", user.Username, newCommunityBalance.String())
 		return nil
 	}
@@ -160,5 +161,6 @@ func HandleUpdateSingleUserGamificationScore(ctx context.Context, t *asynq.Task)
 		return err
 	}
 
+	model.InvalidateUserCache(ctx, user.ID)
 	return nil
 }
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Entry 单次请求的审计信息，由处理函数通过 SetTarget、SetBefore、SetAfter 填充
+type Entry struct {
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// Middleware 记录修改类请求的审计日志，仅在请求成功时写入
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		entry := &Entry{}
+		util.SetToContext(c, EntryObjKey, entry)
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest || c.IsAborted() {
+			return
+		}
+
+		actor, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+		if actor == nil {
+			return
+		}
+
+		log := &model.AuditLog{
+			ActorUserID:   actor.ID,
+			ActorUsername: actor.Username,
+			Action:        c.Request.Method + " " + c.FullPath(),
+			TargetType:    entry.TargetType,
+			TargetID:      entry.TargetID,
+			IP:            c.ClientIP(),
+			UserAgent:     truncate(c.Request.UserAgent(), 255),
+		}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+			log.TraceID = spanContext.TraceID().String()
+		}
+
+		if err := Write(c.Request.Context(), log, entry.Before, entry.After); err != nil {
+			logger.ErrorF(c.Request.Context(), "[Audit] 写入审计日志失败: %s %v", log.Action, err)
+		}
+	}
+}
+
+// Write 计算差异并脱敏后写入审计日志
+func Write(ctx context.Context, log *model.AuditLog, before, after any) error {
+	normalizedBefore, err := normalize(before)
+	if err != nil {
+		return err
+	}
+	normalizedAfter, err := normalize(after)
+	if err != nil {
+		return err
+	}
+
+	// 先基于原始数据计算差异，再对快照脱敏
+	changes := diff(normalizedBefore, normalizedAfter)
+
+	if normalizedBefore != nil {
+		if log.Before, err = json.Marshal(redact(normalizedBefore)); err != nil {
+			return err
+		}
+	}
+	if normalizedAfter != nil {
+		if log.After, err = json.Marshal(redact(normalizedAfter)); err != nil {
+			return err
+		}
+	}
+	if len(changes) > 0 {
+		if log.Diff, err = json.Marshal(changes); err != nil {
+			return err
+		}
+	}
+
+	return db.DB(ctx).Create(log).Error
+}
+
+// SetTarget 设置审计对象
+func SetTarget(c *gin.Context, targetType string, targetID any) {
+	if entry, ok := util.GetFromContext[*Entry](c, EntryObjKey); ok {
+		entry.TargetType = targetType
+		entry.TargetID = fmt.Sprint(targetID)
+	}
+}
+
+// SetBefore 设置变更前的快照，传入值会立即序列化，避免后续修改影响快照
+func SetBefore(c *gin.Context, v any) {
+	if entry, ok := util.GetFromContext[*Entry](c, EntryObjKey); ok {
+		entry.Before = snapshot(v)
+	}
+}
+
+// SetAfter 设置变更后的快照
+func SetAfter(c *gin.Context, v any) {
+	if entry, ok := util.GetFromContext[*Entry](c, EntryObjKey); ok {
+		entry.After = snapshot(v)
+	}
+}
+
+// snapshot 序列化为 JSON 快照
+func snapshot(v any) any {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(raw)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+const (
+	EntryObjKey = "audit_entry_obj"
+)
+
+// RedactedValue 敏感字段脱敏后的值
+const RedactedValue = "******"
+
+// sensitiveFields 写入审计日志前需要脱敏的字段
+var sensitiveFields = map[string]struct{}{
+	"pay_key":       {},
+	"sign_key":      {},
+	"client_secret": {},
+	"secret":        {},
+	"password":      {},
+	"token":         {},
+	"access_token":  {},
+}
+
+// 审计对象类型
+const (
+	TargetSystemConfig       = "system_config"
+	TargetUserPayConfig      = "user_pay_config"
+	TargetUser               = "user"
+	TargetBalanceAdjustment  = "balance_adjustment"
+	TargetMerchantReputation = "merchant_reputation"
+	TargetMerchantAPIKey     = "merchant_api_key"
+	TargetRole               = "role"
+	TargetAnalytics          = "analytics"
+	TargetOrder              = "order"
+	TargetPeriodicTask       = "periodic_task"
+	TargetTaskQueue          = "task_queue"
+	TargetTask               = "task"
+)
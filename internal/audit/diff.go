@@ -0,0 +1,113 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldChange 单个字段的变更
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// normalize 将任意值序列化为通用 JSON 结构
+func normalize(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isSensitive 判断字段是否需要脱敏
+func isSensitive(field string) bool {
+	_, ok := sensitiveFields[field]
+	return ok
+}
+
+// redactValue 脱敏单个值，空值保持原样以便区分“未设置”和“已设置”
+func redactValue(v any) any {
+	if v == nil || v == "" {
+		return v
+	}
+	return RedactedValue
+}
+
+// redact 递归脱敏敏感字段
+func redact(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if isSensitive(k) {
+				value[k] = redactValue(item)
+				continue
+			}
+			value[k] = redact(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redact(item)
+		}
+	}
+	return v
+}
+
+// diff 基于脱敏前的数据计算字段差异，敏感字段只体现发生了变化，非对象类型整体比较
+func diff(before, after any) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if !beforeIsMap || !afterIsMap {
+		if !reflect.DeepEqual(before, after) {
+			changes[""] = FieldChange{Before: before, After: after}
+		}
+		return changes
+	}
+
+	for k, b := range beforeMap {
+		if a, ok := afterMap[k]; !ok || !reflect.DeepEqual(a, b) {
+			changes[k] = FieldChange{Before: b, After: afterMap[k]}
+		}
+	}
+	for k, a := range afterMap {
+		if _, ok := beforeMap[k]; !ok {
+			changes[k] = FieldChange{Before: nil, After: a}
+		}
+	}
+
+	for k, change := range changes {
+		if isSensitive(k) {
+			changes[k] = FieldChange{Before: redactValue(change.Before), After: redactValue(change.After)}
+		} else {
+			changes[k] = FieldChange{Before: redact(change.Before), After: redact(change.After)}
+		}
+	}
+	return changes
+}
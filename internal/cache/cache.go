@@ -0,0 +1,231 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entry 缓存项
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache 进程内键值缓存，带过期时间与命中统计
+// 失效通过 Redis 广播到所有实例，过期时间兜底未广播到的变更
+type Cache[K comparable, V any] struct {
+	name     string
+	ttl      time.Duration
+	parseKey func(string) (K, error)
+
+	mu      sync.RWMutex
+	entries map[K]entry[V]
+	// generation 每次失效递增，加载期间发生失效时丢弃加载结果，避免旧数据回填
+	generation uint64
+	// lastPurge 上次清理过期项的时间，写入时每隔一个 TTL 清理一次
+	lastPurge time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Stats 单个缓存的命中统计
+type Stats struct {
+	Name    string  `json:"name"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Size    int     `json:"size"`
+}
+
+// invalidator 注册表中缓存的非泛型视图，供广播失效与统计使用
+type invalidator interface {
+	stats() Stats
+	removeRaw(keys []string)
+	clear()
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]invalidator{}
+)
+
+// StringKey 字符串键解析
+func StringKey(s string) (string, error) {
+	return s, nil
+}
+
+// Uint64Key 整数 ID 键解析
+func Uint64Key(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// New 创建并注册缓存，name 在进程内唯一，用于广播失效与统计
+// parseKey 将广播中的字符串键还原为缓存键
+func New[K comparable, V any](name string, ttl time.Duration, parseKey func(string) (K, error)) *Cache[K, V] {
+	c := &Cache[K, V]{
+		name:     name,
+		ttl:      ttl,
+		parseKey: parseKey,
+		entries:  make(map[K]entry[V]),
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("cache %q already registered", name))
+	}
+	registry[name] = c
+	return c
+}
+
+// Get 读取未过期的缓存项
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok && time.Now().Before(e.expiresAt) {
+		c.hits.Add(1)
+		return e.value, true
+	}
+	c.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// GetOrLoad 读取缓存，未命中时调用 load 加载并写入缓存，加载失败不写入
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+	}
+	if now.Sub(c.lastPurge) > c.ttl {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastPurge = now
+	}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate 删除本实例缓存项并广播给其他实例，应在数据变更提交后调用
+func (c *Cache[K, V]) Invalidate(ctx context.Context, keys ...K) {
+	if len(keys) == 0 {
+		return
+	}
+	c.remove(keys)
+
+	rawKeys := make([]string, len(keys))
+	for i, key := range keys {
+		rawKeys[i] = fmt.Sprint(key)
+	}
+	publish(ctx, &invalidationMessage{Cache: c.name, Keys: rawKeys})
+}
+
+// InvalidateAll 清空本实例缓存并广播给其他实例
+func (c *Cache[K, V]) InvalidateAll(ctx context.Context) {
+	c.clear()
+	publish(ctx, &invalidationMessage{Cache: c.name, All: true})
+}
+
+func (c *Cache[K, V]) remove(keys []K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	c.generation++
+}
+
+func (c *Cache[K, V]) removeRaw(rawKeys []string) {
+	keys := make([]K, 0, len(rawKeys))
+	for _, raw := range rawKeys {
+		key, err := c.parseKey(raw)
+		if err != nil {
+			// 无法解析的键按全部失效处理，宁可多查一次数据库
+			c.clear()
+			return
+		}
+		keys = append(keys, key)
+	}
+	c.remove(keys)
+}
+
+func (c *Cache[K, V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]entry[V])
+	c.generation++
+}
+
+func (c *Cache[K, V]) stats() Stats {
+	c.mu.RLock()
+	size := len(c.entries)
+	c.mu.RUnlock()
+
+	s := Stats{Name: c.name, Hits: c.hits.Load(), Misses: c.misses.Load(), Size: size}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// AllStats 返回本实例全部缓存的命中统计，按名称排序
+func AllStats() []Stats {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	result := make([]Stats, 0, len(registry))
+	for _, c := range registry {
+		result = append(result, c.stats())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// lookup 按名称查找已注册的缓存
+func lookup(name string) (invalidator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := registry[name]
+	return c, ok
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+)
+
+// InvalidationChannel 缓存失效广播的 Redis 频道
+const InvalidationChannel = "cache:invalidate"
+
+// invalidationMessage 缓存失效广播内容
+type invalidationMessage struct {
+	Cache string   `json:"cache"`
+	Keys  []string `json:"keys,omitempty"`
+	All   bool     `json:"all,omitempty"`
+}
+
+// publish 广播缓存失效，Redis 未启用时仅本实例失效，失败仅记录日志
+func publish(ctx context.Context, msg *invalidationMessage) {
+	if db.Redis == nil {
+		return
+	}
+
+	payload, _ := json.Marshal(msg)
+	if err := db.Redis.Publish(ctx, db.PrefixedKey(InvalidationChannel), payload).Err(); err != nil {
+		logger.ErrorF(ctx, "广播缓存[%s]失效失败: %v", msg.Cache, err)
+	}
+}
+
+// StartInvalidationListener 在后台订阅缓存失效广播直至 ctx 结束，Redis 未启用时不订阅
+// 本实例发出的广播也会收到，重复删除不影响正确性
+func StartInvalidationListener(ctx context.Context) {
+	if db.Redis == nil {
+		return
+	}
+
+	pubSub := db.Redis.Subscribe(ctx, db.PrefixedKey(InvalidationChannel))
+	go func() {
+		defer func() { _ = pubSub.Close() }()
+
+		ch := pubSub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				handleInvalidation(ctx, msg.Payload)
+			}
+		}
+	}()
+}
+
+// handleInvalidation 按广播内容删除本实例缓存
+func handleInvalidation(ctx context.Context, payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		logger.ErrorF(ctx, "解析缓存失效广播失败: %v", err)
+		return
+	}
+
+	c, ok := lookup(msg.Cache)
+	if !ok {
+		return
+	}
+	if msg.All {
+		c.clear()
+		return
+	}
+	c.removeRaw(msg.Keys)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/model"
+)
+
+// ErrChannelUnavailable 渠道未配置
+var ErrChannelUnavailable = errors.New("通知渠道未启用")
+
+// Message 发往站外渠道的通知内容
+type Message struct {
+	ID        uint64                     `json:"id"`
+	Category  model.NotificationCategory `json:"category"`
+	Type      model.NotificationType     `json:"type"`
+	Title     string                     `json:"title"`
+	Content   string                     `json:"content"`
+	OrderID   *uint64                    `json:"order_id,omitempty"`
+	DisputeID *uint64                    `json:"dispute_id,omitempty"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+// Destination 通知接收方
+type Destination struct {
+	Username string // 站内用户名
+	Target   string // 渠道地址：邮箱、Webhook URL 或 linux.do 用户名
+	Secret   string // Webhook 签名密钥
+}
+
+// Channel 站外通知渠道
+type Channel interface {
+	// Type 渠道类型
+	Type() model.NotificationChannelType
+	// Validate 校验用户填写的渠道地址
+	Validate(dest *Destination) error
+	// Send 发送通知，返回错误时由任务队列重试
+	Send(ctx context.Context, dest *Destination, msg *Message) error
+}
+
+var (
+	registry     map[model.NotificationChannelType]Channel
+	registryOnce sync.Once
+)
+
+// loadRegistry 根据配置初始化已启用的渠道
+func loadRegistry() {
+	registry = make(map[model.NotificationChannelType]Channel)
+
+	timeout := time.Duration(config.Config.Notify.RequestTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	smtpCfg := config.Config.Notify.SMTP
+	if smtpCfg.Host != "" {
+		register(&SMTPChannel{
+			Host:     smtpCfg.Host,
+			Port:     smtpCfg.Port,
+			Username: smtpCfg.Username,
+			Password: smtpCfg.Password,
+			From:     smtpCfg.From,
+			TLS:      smtpCfg.TLS,
+			Timeout:  timeout,
+		})
+	}
+
+	var webhookAllowed []netip.Prefix
+	for _, cidr := range config.Config.Notify.WebhookAllowedCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			log.Printf("[Channel] invalid webhook_allowed_cidrs entry %q: %v\n", cidr, err)
+			continue
+		}
+		webhookAllowed = append(webhookAllowed, prefix)
+	}
+	register(NewWebhookChannel(timeout, webhookAllowed...))
+
+	linuxDoCfg := config.Config.LinuxDo
+	if linuxDoCfg.ApiKey != "" && linuxDoCfg.BaseURL != "" {
+		register(NewDiscourseChannel(linuxDoCfg.BaseURL, linuxDoCfg.ApiKey, linuxDoCfg.ApiUsername, timeout))
+	}
+}
+
+func register(ch Channel) {
+	registry[ch.Type()] = ch
+}
+
+// Register 注册渠道，同类型渠道后注册的覆盖先注册的，可用于替换为本地测试服务
+func Register(ch Channel) {
+	registryOnce.Do(loadRegistry)
+	register(ch)
+}
+
+// Get 获取已启用的渠道
+func Get(channelType model.NotificationChannelType) (Channel, error) {
+	registryOnce.Do(loadRegistry)
+	ch, ok := registry[channelType]
+	if !ok {
+		return nil, ErrChannelUnavailable
+	}
+	return ch, nil
+}
+
+// Available 返回所有已启用的渠道类型
+func Available() []model.NotificationChannelType {
+	registryOnce.Do(loadRegistry)
+	types := make([]model.NotificationChannelType, 0, len(registry))
+	for _, t := range []model.NotificationChannelType{
+		model.NotificationChannelEmail,
+		model.NotificationChannelWebhook,
+		model.NotificationChannelDiscourse,
+	} {
+		if _, ok := registry[t]; ok {
+			types = append(types, t)
+		}
+	}
+	return types
+}
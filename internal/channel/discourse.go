@@ -0,0 +1,110 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+)
+
+var discourseUsernamePattern = regexp.MustCompile(`^[\w.-]{1,60}$`)
+
+// DiscourseChannel 通过 Discourse API 发送 linux.do 私信
+type DiscourseChannel struct {
+	baseURL     string
+	apiKey      string
+	apiUsername string
+	client      *http.Client
+}
+
+// NewDiscourseChannel 创建 Discourse 私信渠道，baseURL 可指向本地测试服务
+func NewDiscourseChannel(baseURL, apiKey, apiUsername string, timeout time.Duration) *DiscourseChannel {
+	if apiUsername == "" {
+		apiUsername = "system"
+	}
+	return &DiscourseChannel{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      apiKey,
+		apiUsername: apiUsername,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *DiscourseChannel) Type() model.NotificationChannelType {
+	return model.NotificationChannelDiscourse
+}
+
+func (c *DiscourseChannel) Validate(dest *Destination) error {
+	if !discourseUsernamePattern.MatchString(c.recipient(dest)) {
+		return errors.New("linux.do 用户名格式不正确")
+	}
+	return nil
+}
+
+// recipient 未填写渠道地址时发送给站内同名用户
+func (c *DiscourseChannel) recipient(dest *Destination) string {
+	if dest.Target != "" {
+		return dest.Target
+	}
+	return dest.Username
+}
+
+func (c *DiscourseChannel) Send(ctx context.Context, dest *Destination, msg *Message) error {
+	if err := c.Validate(dest); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"title":             msg.Title,
+		"raw":               msg.Content,
+		"target_recipients": c.recipient(dest),
+		"archetype":         "private_message",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/posts.json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 Discourse 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Api-Key", c.apiKey)
+	req.Header.Set("Api-Username", c.apiUsername)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 Discourse 失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Discourse 返回状态码 %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
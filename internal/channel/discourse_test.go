@@ -0,0 +1,112 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscourseSend(t *testing.T) {
+	var (
+		gotPath   string
+		gotKey    string
+		gotUser   string
+		gotFields map[string]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+		gotKey = r.Header.Get("Api-Key")
+		gotUser = r.Header.Get("Api-Username")
+		_ = json.NewDecoder(r.Body).Decode(&gotFields)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	ch := NewDiscourseChannel(server.URL+"/", "key", "", 5*time.Second)
+	msg := testMessage()
+	if err := ch.Send(context.Background(), &Destination{Username: "alice"}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if gotPath != "POST /posts.json" {
+		t.Errorf("request = %q", gotPath)
+	}
+	if gotKey != "key" || gotUser != "system" {
+		t.Errorf("Api-Key = %q, Api-Username = %q", gotKey, gotUser)
+	}
+	want := map[string]string{
+		"title":             msg.Title,
+		"raw":               msg.Content,
+		"target_recipients": "alice",
+		"archetype":         "private_message",
+	}
+	for k, v := range want {
+		if gotFields[k] != v {
+			t.Errorf("%s = %q, want %q", k, gotFields[k], v)
+		}
+	}
+}
+
+func TestDiscourseSendToTarget(t *testing.T) {
+	var recipient string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var fields map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&fields)
+		recipient = fields["target_recipients"]
+	}))
+	defer server.Close()
+
+	ch := NewDiscourseChannel(server.URL, "key", "bot", 5*time.Second)
+	if err := ch.Send(context.Background(), &Destination{Username: "alice", Target: "bob"}, testMessage()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if recipient != "bob" {
+		t.Errorf("target_recipients = %q, want bob", recipient)
+	}
+}
+
+func TestDiscourseSendReportsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":["recipient not found"]}`))
+	}))
+	defer server.Close()
+
+	ch := NewDiscourseChannel(server.URL, "key", "bot", 5*time.Second)
+	err := ch.Send(context.Background(), &Destination{Username: "alice"}, testMessage())
+	if err == nil || !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "recipient not found") {
+		t.Fatalf("Send() error = %v, want status and response body", err)
+	}
+}
+
+func TestDiscourseValidate(t *testing.T) {
+	ch := NewDiscourseChannel("http://127.0.0.1", "key", "bot", time.Second)
+	for _, dest := range []Destination{{}, {Target: "a b"}, {Target: strings.Repeat("a", 61)}, {Target: "../admin"}} {
+		if err := ch.Validate(&dest); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", dest)
+		}
+	}
+	if err := ch.Validate(&Destination{Username: "alice.b-c_1"}); err != nil {
+		t.Errorf("Validate(valid username) error = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+)
+
+// SMTPChannel 通过 SMTP 发送邮件通知
+type SMTPChannel struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      bool // true 为隐式 TLS，false 时若服务器支持则升级 STARTTLS
+	Timeout  time.Duration
+}
+
+func (c *SMTPChannel) Type() model.NotificationChannelType {
+	return model.NotificationChannelEmail
+}
+
+func (c *SMTPChannel) Validate(dest *Destination) error {
+	addr, err := mail.ParseAddress(dest.Target)
+	if err != nil || addr.Address != dest.Target {
+		return errors.New("邮箱地址格式不正确")
+	}
+	return nil
+}
+
+func (c *SMTPChannel) Send(ctx context.Context, dest *Destination, msg *Message) error {
+	if err := c.Validate(dest); err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(c.From)
+	if err != nil {
+		return fmt.Errorf("发件人地址配置错误: %w", err)
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(c.Timeout))
+
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("SMTP 握手失败: %w", err)
+	}
+	defer client.Close()
+
+	if !c.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: c.Host}); err != nil {
+				return fmt.Errorf("SMTP STARTTLS 失败: %w", err)
+			}
+		}
+	}
+
+	if c.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", c.Username, c.Password, c.Host)); err != nil {
+				return fmt.Errorf("SMTP 认证失败: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM 失败: %w", err)
+	}
+	if err := client.Rcpt(dest.Target); err != nil {
+		return fmt.Errorf("SMTP RCPT TO 失败: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA 失败: %w", err)
+	}
+	if _, err := w.Write(buildMail(from, dest.Target, msg)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("写入邮件内容失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("提交邮件失败: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (c *SMTPChannel) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	dialer := &net.Dialer{Timeout: c.Timeout}
+	if c.TLS {
+		return (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: c.Host}}).DialContext(ctx, "tcp", addr)
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// buildMail 构造 UTF-8 纯文本邮件，主题使用 RFC 2047 编码，正文使用 base64 编码
+func buildMail(from *mail.Address, to string, msg *Message) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from.String() + "\r\n")
+	buf.WriteString("To: " + to + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Title) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(msg.Content))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
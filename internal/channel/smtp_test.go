@@ -0,0 +1,176 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession 本地 SMTP 桩服务收到的一次投递
+type smtpSession struct {
+	from string
+	rcpt []string
+	data string
+}
+
+// startSMTPStub 启动只支持明文、无认证的最小 SMTP 服务，rejectRcpt 为 true 时拒绝收件人
+func startSMTPStub(t *testing.T, rejectRcpt bool) (port int, sessions <-chan smtpSession) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	ch := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		tp := textproto.NewConn(conn)
+		reply := func(line string) { _ = tp.PrintfLine("%s", line) }
+
+		var session smtpSession
+		reply("220 stub ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				reply("250 stub")
+			case "MAIL":
+				session.from = arg
+				reply("250 OK")
+			case "RCPT":
+				if rejectRcpt {
+					reply("550 no such user")
+					continue
+				}
+				session.rcpt = append(session.rcpt, arg)
+				reply("250 OK")
+			case "DATA":
+				reply("354 end with <CR><LF>.<CR><LF>")
+				lines, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				session.data = strings.Join(lines, "\n")
+				reply("250 OK")
+			case "QUIT":
+				reply("221 bye")
+				ch <- session
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, ch
+}
+
+func TestSMTPSend(t *testing.T) {
+	port, sessions := startSMTPStub(t, false)
+
+	ch := &SMTPChannel{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "LINUX DO PAY <noreply@example.com>",
+		Timeout: 5 * time.Second,
+	}
+	msg := testMessage()
+	if err := ch.Send(context.Background(), &Destination{Target: "alice@example.com"}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var session smtpSession
+	select {
+	case session = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stub did not receive the mail")
+	}
+
+	if session.from != "FROM:<noreply@example.com>" {
+		t.Errorf("MAIL = %q", session.from)
+	}
+	if len(session.rcpt) != 1 || session.rcpt[0] != "TO:<alice@example.com>" {
+		t.Errorf("RCPT = %q", session.rcpt)
+	}
+
+	header, body, ok := strings.Cut(session.data, "\n\n")
+	if !ok {
+		t.Fatalf("mail has no body: %q", session.data)
+	}
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\n\n")))
+	fields, err := reader.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("parse header: %v", err)
+	}
+	if to := fields.Get("To"); to != "alice@example.com" {
+		t.Errorf("To = %q", to)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(fields.Get("Subject"))
+	if err != nil || subject != msg.Title {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Title)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\n", ""))
+	if err != nil || string(decoded) != msg.Content {
+		t.Errorf("body = %q (%v), want %q", decoded, err, msg.Content)
+	}
+}
+
+func TestSMTPSendRejectedRecipient(t *testing.T) {
+	port, _ := startSMTPStub(t, true)
+
+	ch := &SMTPChannel{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "noreply@example.com",
+		Timeout: 5 * time.Second,
+	}
+	if err := ch.Send(context.Background(), &Destination{Target: "nobody@example.com"}, testMessage()); err == nil {
+		t.Fatal("Send() succeeded with a rejected recipient")
+	}
+}
+
+func TestSMTPValidate(t *testing.T) {
+	ch := &SMTPChannel{}
+	for _, target := range []string{"", "alice", "Alice <alice@example.com>", "alice@example.com\r\nBcc: eve@example.com"} {
+		if err := ch.Validate(&Destination{Target: target}); err == nil {
+			t.Errorf("Validate(%q) = nil, want error", target)
+		}
+	}
+	if err := ch.Validate(&Destination{Target: "alice@example.com"}); err != nil {
+		t.Errorf("Validate(valid address) error = %v", err)
+	}
+}
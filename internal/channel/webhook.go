@@ -0,0 +1,190 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+)
+
+const (
+	WebhookTimestampHeader = "X-Pay-Timestamp"
+	WebhookSignatureHeader = "X-Pay-Signature"
+
+	webhookResolveTimeout = 3 * time.Second
+)
+
+var errWebhookAddressNotAllowed = errors.New("Webhook 地址不能指向内网、本机或保留地址")
+
+// webhookBlockedPrefixes net/netip 未覆盖的非公网地址段
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// WebhookChannel 向用户配置的地址推送 JSON 通知
+// 设置了密钥时附带签名：hex(HMAC-SHA256(secret, timestamp + "." + body))
+type WebhookChannel struct {
+	client  *http.Client
+	allowed []netip.Prefix
+}
+
+// NewWebhookChannel 创建 Webhook 渠道，allowed 为额外放行的非公网地址段，用于本地测试服务或内网接收方
+// 地址由用户填写，建立连接时校验实际连接的 IP 以防 DNS 重绑定，且不跟随重定向，避免请求被引向内网
+func NewWebhookChannel(timeout time.Duration, allowed ...netip.Prefix) *WebhookChannel {
+	c := &WebhookChannel{allowed: allowed}
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !c.addrAllowed(addrPort.Addr()) {
+				return errWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	c.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return c
+}
+
+func (c *WebhookChannel) Type() model.NotificationChannelType {
+	return model.NotificationChannelWebhook
+}
+
+func (c *WebhookChannel) Validate(dest *Destination) error {
+	u, err := url.Parse(dest.Target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("Webhook 地址必须是 http(s) URL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return errors.New("Webhook 地址无法解析")
+	}
+	for _, addr := range addrs {
+		if !c.addrAllowed(addr) {
+			return errWebhookAddressNotAllowed
+		}
+	}
+	return nil
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, dest *Destination, msg *Message) error {
+	if err := c.Validate(dest); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dest.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 Webhook 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if dest.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(dest.Secret, timestamp, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 Webhook 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook 返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook 计算 Webhook 签名，接收方可用同样的方法校验
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// addrAllowed 判断是否允许连接该地址：公网地址或在放行地址段内
+func (c *WebhookChannel) addrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range c.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return isPublicAddr(addr)
+}
+
+// isPublicAddr 判断是否为可访问的公网地址
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
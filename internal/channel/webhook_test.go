@@ -0,0 +1,178 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+)
+
+// loopbackPrefixes 放行本地测试服务所在的回环地址
+var loopbackPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("::1/128"),
+}
+
+func testMessage() *Message {
+	orderID := uint64(42)
+	return &Message{
+		ID:        7,
+		Category:  model.NotificationCategoryPayment,
+		Type:      model.NotificationTypePaymentReceived,
+		Title:     "收到付款",
+		Content:   "alice 支付了订单「测试」，金额 1.00",
+		OrderID:   &orderID,
+		CreatedAt: time.Unix(1700000000, 0),
+	}
+}
+
+func TestWebhookSendSignsPayload(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotTimestamp string
+		gotSignature string
+		gotType      string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotTimestamp = r.Header.Get(WebhookTimestampHeader)
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel(5*time.Second, loopbackPrefixes...)
+	msg := testMessage()
+	if err := ch.Send(context.Background(), &Destination{Target: server.URL, Secret: "s3cret"}, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotType)
+	}
+	if gotTimestamp == "" {
+		t.Fatal("missing timestamp header")
+	}
+	if want := SignWebhook("s3cret", gotTimestamp, gotBody); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+
+	var got Message
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if got.ID != msg.ID || got.Title != msg.Title || got.Content != msg.Content || got.OrderID == nil || *got.OrderID != *msg.OrderID {
+		t.Errorf("body = %+v, want %+v", got, msg)
+	}
+}
+
+func TestWebhookSendWithoutSecretOmitsSignature(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel(5*time.Second, loopbackPrefixes...)
+	if err := ch.Send(context.Background(), &Destination{Target: server.URL}, testMessage()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if headers.Get(WebhookTimestampHeader) != "" || headers.Get(WebhookSignatureHeader) != "" {
+		t.Errorf("unexpected signature headers: %v", headers)
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		redirected.Add(1)
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel(5*time.Second, loopbackPrefixes...)
+	if err := ch.Send(context.Background(), &Destination{Target: server.URL}, testMessage()); err == nil {
+		t.Fatal("Send() succeeded on a redirect response")
+	}
+	if n := redirected.Load(); n != 0 {
+		t.Errorf("redirect target was requested %d times", n)
+	}
+}
+
+func TestWebhookRejectsNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel(5*time.Second, loopbackPrefixes...)
+	if err := ch.Send(context.Background(), &Destination{Target: server.URL}, testMessage()); err == nil {
+		t.Fatal("Send() succeeded on a 500 response")
+	}
+}
+
+func TestWebhookBlocksLoopbackByDefault(t *testing.T) {
+	var requested atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requested.Add(1)
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel(5 * time.Second)
+	err := ch.Send(context.Background(), &Destination{Target: server.URL}, testMessage())
+	if !errors.Is(err, errWebhookAddressNotAllowed) {
+		t.Fatalf("Send() error = %v, want %v", err, errWebhookAddressNotAllowed)
+	}
+	if n := requested.Load(); n != 0 {
+		t.Errorf("blocked server was requested %d times", n)
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	ch := NewWebhookChannel(5 * time.Second)
+	for _, target := range []string{
+		"ftp://example.com/hook",
+		"http://",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+	} {
+		if err := ch.Validate(&Destination{Target: target}); err == nil {
+			t.Errorf("Validate(%q) = nil, want error", target)
+		}
+	}
+	if err := ch.Validate(&Destination{Target: "https://1.1.1.1/hook"}); err != nil {
+		t.Errorf("Validate(public address) error = %v", err)
+	}
+}
@@ -22,8 +22,9 @@ import (
 )
 
 var apiCmd = &cobra.Command{
-	Use:   "api",
-	Short: "CDK API",
+	Use:    "api",
+	Short:  "CDK API",
+	PreRun: runMigrate,
 	Run: func(cmd *cobra.Command, args []string) {
 		router.Serve()
 	},
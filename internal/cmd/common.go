@@ -0,0 +1,113 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db/migrator"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// 进程退出码
+const (
+	ExitOK      = 0
+	ExitFailure = 1 // 执行失败
+	ExitUsage   = 2 // 参数或命令错误
+)
+
+const (
+	// cliActor 命令行操作写入审计日志时的操作者名称
+	cliActor = "cli"
+)
+
+// usageError 参数错误，以 ExitUsage 退出
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// exitCode 根据错误类型返回退出码
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var usageErr *usageError
+	if errors.As(err, &usageErr) ||
+		strings.HasPrefix(err.Error(), "unknown command") ||
+		strings.HasPrefix(err.Error(), "required flag") {
+		return ExitUsage
+	}
+	return ExitFailure
+}
+
+// exactArgs 校验位置参数个数，不符合时返回参数错误
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+			return &usageError{err: err}
+		}
+		return nil
+	}
+}
+
+// runMigrate 服务类命令启动前执行数据库迁移
+func runMigrate(*cobra.Command, []string) {
+	migrator.Migrate()
+}
+
+// requireDatabase 运维命令依赖数据库，未启用时直接失败
+func requireDatabase(*cobra.Command, []string) error {
+	if !config.Config.Database.Enabled {
+		return errors.New("数据库未启用，请检查配置文件中的 database.enabled")
+	}
+	return nil
+}
+
+// printJSON 以缩进 JSON 输出结果
+func printJSON(cmd *cobra.Command, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return err
+}
+
+// writeAuditLog 记录命令行发起的变更，操作者记为 cli
+func writeAuditLog(ctx context.Context, cmd *cobra.Command, targetType string, targetID any, before, after any) error {
+	return audit.Write(ctx, &model.AuditLog{
+		ActorUsername: cliActor,
+		Action:        "CLI " + cmd.CommandPath(),
+		TargetType:    targetType,
+		TargetID:      fmt.Sprint(targetID),
+	}, before, after)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var configDescription string
+
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "系统配置管理",
+	PersistentPreRunE: requireDatabase,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "查看系统配置，不指定 key 时列出全部",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return &usageError{err: err}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if len(args) == 0 {
+			var configs []model.SystemConfig
+			if err := db.DB(ctx).Order("key ASC").Find(&configs).Error; err != nil {
+				return err
+			}
+			return printJSON(cmd, configs)
+		}
+
+		var sc model.SystemConfig
+		if err := db.DB(ctx).Where("key = ?", args[0]).First(&sc).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("配置 %s 不存在", args[0])
+			}
+			return err
+		}
+		return printJSON(cmd, sc)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "新增或更新系统配置，并同步 Redis 缓存",
+	Args:  exactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		key, value := args[0], args[1]
+		if len(key) > 64 || len(value) > 255 {
+			return &usageError{err: errors.New("key 最长 64 个字符，value 最长 255 个字符")}
+		}
+
+		var before *model.SystemConfig
+		var sc model.SystemConfig
+		if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Where("key = ?", key).First(&sc).Error
+			switch {
+			case err == nil:
+				snapshot := sc
+				before = &snapshot
+				updates := map[string]interface{}{"value": value}
+				if cmd.Flags().Changed("description") {
+					updates["description"] = configDescription
+				}
+				if err := tx.Model(&sc).Updates(updates).Error; err != nil {
+					return err
+				}
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				sc = model.SystemConfig{Key: key, Value: value, Description: configDescription}
+				if err := tx.Create(&sc).Error; err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+
+			// 与管理后台一致，在事务内同步缓存，缓存写入失败时回滚
+			if db.Redis != nil {
+				return db.HSetJSON(ctx, model.SystemConfigRedisHashKey, key, &sc)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := writeAuditLog(ctx, cmd, audit.TargetSystemConfig, key, before, sc); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s = %s\n", sc.Key, sc.Value)
+		return nil
+	},
+}
+
+func init() {
+	configSetCmd.Flags().StringVar(&configDescription, "description", "", "配置说明")
+
+	configCmd.AddCommand(configGetCmd, configSetCmd)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linux-do/pay/internal/db/migrator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateTarget  int64
+	migrateSteps   int
+	migrateConfirm bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:               "migrate",
+	Short:             "数据库迁移",
+	PersistentPreRunE: requireDatabase,
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "执行待执行的迁移",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applied, err := migrator.Up(cmd.Context(), migrateTarget)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已执行 %d 个迁移\n", len(applied))
+		return nil
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "回滚最近执行的迁移",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateSteps <= 0 {
+			return &usageError{err: errors.New("--steps 必须大于 0")}
+		}
+		if !migrateConfirm {
+			return &usageError{err: errors.New("回滚可能删除数据，请添加 --confirm 确认")}
+		}
+
+		reverted, err := migrator.Down(cmd.Context(), migrateSteps)
+		if err != nil {
+			return err
+		}
+		for _, m := range reverted {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已回滚 %d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看迁移执行状态，存在待执行迁移时以非零码退出",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := migrator.Statuses(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		out := cmd.OutOrStdout()
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			} else {
+				pending++
+			}
+			_, _ = fmt.Fprintf(out, "%06d  %-32s %-4s %s\n", status.Version, status.Name, status.Kind, state)
+		}
+
+		if pending > 0 {
+			return fmt.Errorf("%d migrations pending", pending)
+		}
+		return nil
+	},
+}
+
+var migrateDryRunCmd = &cobra.Command{
+	Use:   "dry-run",
+	Short: "输出待执行迁移的 SQL，不修改数据库",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return migrator.DryRun(cmd.Context(), cmd.OutOrStdout())
+	},
+}
+
+var migrateDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "检查数据库结构与模型定义的差异，用于发现遗漏的迁移",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := migrator.Drift(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		drifted := 0
+		out := cmd.OutOrStdout()
+		for i := range statuses {
+			status := &statuses[i]
+			switch {
+			case !status.Exists:
+				_, _ = fmt.Fprintf(out, "%-36s missing table\n", status.Table)
+			case status.Drifted():
+				_, _ = fmt.Fprintf(out, "%-36s missing columns=%v indexes=%v\n", status.Table, status.MissingColumns, status.MissingIndexes)
+			default:
+				_, _ = fmt.Fprintf(out, "%-36s ok\n", status.Table)
+			}
+			if status.Drifted() {
+				drifted++
+			}
+		}
+
+		if drifted > 0 {
+			return fmt.Errorf("%d tables differ from models", drifted)
+		}
+		return nil
+	},
+}
+
+func init() {
+	migrateUpCmd.Flags().Int64Var(&migrateTarget, "to", 0, "只执行到指定版本，默认执行全部")
+	migrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "回滚的迁移个数")
+	migrateDownCmd.Flags().BoolVar(&migrateConfirm, "confirm", false, "确认执行回滚")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateDryRunCmd, migrateDriftCmd)
+}
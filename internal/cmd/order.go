@@ -0,0 +1,114 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var expireStaleBatchSize int
+
+var orderCmd = &cobra.Command{
+	Use:               "order",
+	Short:             "订单运维",
+	PersistentPreRunE: requireDatabase,
+}
+
+var orderShowCmd = &cobra.Command{
+	Use:   "show <order_id>",
+	Short: "查看订单详情及最近的商户回调记录",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		order, err := loadOrder(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		var notifyLogs []model.MerchantNotifyLog
+		if err := db.DB(ctx).
+			Where("order_id = ?", order.ID).
+			Order("id DESC").
+			Limit(10).
+			Find(&notifyLogs).Error; err != nil {
+			return err
+		}
+
+		return printJSON(cmd, map[string]any{
+			"order":       order,
+			"notify_logs": notifyLogs,
+		})
+	},
+}
+
+var orderExpireStaleCmd = &cobra.Command{
+	Use:   "expire-stale",
+	Short: "将已到期仍待支付的订单置为过期",
+	Args:  exactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if expireStaleBatchSize <= 0 {
+			return &usageError{err: errors.New("--batch 必须大于 0")}
+		}
+
+		count, err := service.ExpireDueOrders(cmd.Context(), expireStaleBatchSize)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已过期 %d 笔订单\n", count)
+		return nil
+	},
+}
+
+// loadOrder 按订单 ID 加载订单，订单号为补零后的 ID，同样可以解析
+func loadOrder(ctx context.Context, ref string) (*model.Order, error) {
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil {
+		return nil, &usageError{err: fmt.Errorf("无效的订单 ID: %s", ref)}
+	}
+
+	var order model.Order
+	if err := db.DB(ctx).
+		Table("orders").
+		Select("orders.*, payer.username AS payer_username, payee.username AS payee_username").
+		Joins("LEFT JOIN users AS payer ON payer.id = orders.payer_user_id").
+		Joins("LEFT JOIN users AS payee ON payee.id = orders.payee_user_id").
+		Where("orders.id = ?", id).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("订单 %s 不存在", ref)
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func init() {
+	orderExpireStaleCmd.Flags().IntVar(&expireStaleBatchSize, "batch", payment.ExpireSweepBatchSize, "每批处理的订单数")
+
+	orderCmd.AddCommand(orderShowCmd, orderExpireStaleCmd)
+}
@@ -17,40 +17,43 @@ limitations under the License.
 package cmd
 
 import (
-	"github.com/linux-do/pay/internal/db/migrator"
-	"github.com/spf13/cobra"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+
+	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
-	Use: "linux-do-cdk",
-	PreRun: func(cmd *cobra.Command, args []string) {
-		migrator.Migrate()
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			log.Fatalf("[CMD] please provide a command\n")
-		}
-		appMode := args[0]
-		switch appMode {
-		case "api":
-			apiCmd.Run(apiCmd, args)
-		case "scheduler":
-			schedulerCmd.Run(schedulerCmd, args)
-		case "worker":
-			workerCmd.Run(workerCmd, args)
-		default:
-			log.Fatal("[CMD] unknown app mode\n")
-		}
+	Use:           "linux-do-cdk",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return &usageError{err: errors.New("please provide a command")}
 	},
 }
 
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+
+	rootCmd.AddCommand(apiCmd, schedulerCmd, workerCmd)
+	rootCmd.AddCommand(migrateCmd, userCmd, configCmd, orderCmd, webhookCmd)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("[CMD] execute failed; %s\n", err)
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	code := exitCode(err)
+	log.Printf("[CMD] execute failed; %s\n", err)
+	if code == ExitUsage {
+		_, _ = fmt.Fprint(os.Stderr, cmd.UsageString())
 	}
+	os.Exit(code)
 }
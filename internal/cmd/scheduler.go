@@ -24,8 +24,9 @@ import (
 )
 
 var schedulerCmd = &cobra.Command{
-	Use:   "scheduler",
-	Short: "CDK Scheduler",
+	Use:    "scheduler",
+	Short:  "CDK Scheduler",
+	PreRun: runMigrate,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Println("[Scheduler] 启动定时任务调度服务")
 		if err := schedule.StartScheduler(); err != nil {
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	adminuser "github.com/linux-do/pay/internal/apps/admin/user"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var (
+	userByID      bool
+	grantRoleName string
+	banReason     string
+)
+
+var userCmd = &cobra.Command{
+	Use:               "user",
+	Short:             "用户管理",
+	PersistentPreRunE: requireDatabase,
+}
+
+var userShowCmd = &cobra.Command{
+	Use:   "show <username>",
+	Short: "查看用户信息与角色",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		user, err := loadUser(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		roles, err := model.GetRolesByUserID(db.DB(ctx), user.ID)
+		if err != nil {
+			return err
+		}
+
+		roleNames := make([]string, 0, len(roles))
+		for _, role := range roles {
+			roleNames = append(roleNames, role.Name)
+		}
+
+		return printJSON(cmd, map[string]any{
+			"id":                user.ID,
+			"username":          user.Username,
+			"nickname":          user.Nickname,
+			"trust_level":       user.TrustLevel,
+			"pay_score":         user.PayScore,
+			"available_balance": user.AvailableBalance,
+			"community_balance": user.CommunityBalance,
+			"is_active":         user.IsActive,
+			"is_admin":          user.IsAdmin,
+			"ban_reason":        user.BanReason,
+			"banned_at":         user.BannedAt,
+			"roles":             roleNames,
+			"last_login_at":     user.LastLoginAt,
+			"created_at":        user.CreatedAt,
+		})
+	},
+}
+
+var userGrantAdminCmd = &cobra.Command{
+	Use:   "grant-admin <username>",
+	Short: "为用户追加管理角色，默认为超级管理员",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		user, err := loadUser(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		var role model.Role
+		if err := role.GetByName(db.DB(ctx), grantRoleName); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("角色 %s 不存在", grantRoleName)
+			}
+			return err
+		}
+
+		current, err := model.GetRolesByUserID(db.DB(ctx), user.ID)
+		if err != nil {
+			return err
+		}
+
+		roleIDs := make([]uint64, 0, len(current)+1)
+		before := make([]string, 0, len(current))
+		for _, r := range current {
+			if r.ID == role.ID {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "用户 %s 已拥有角色 %s\n", user.Username, role.Name)
+				return nil
+			}
+			roleIDs = append(roleIDs, r.ID)
+			before = append(before, r.Name)
+		}
+		roleIDs = append(roleIDs, role.ID)
+
+		roles, err := adminuser.AssignRoles(ctx, user, roleIDs, 0)
+		if err != nil {
+			return err
+		}
+
+		after := make([]string, 0, len(roles))
+		for _, r := range roles {
+			after = append(after, r.Name)
+		}
+		if err := writeAuditLog(ctx, cmd, audit.TargetUser, user.ID, map[string]any{"roles": before}, map[string]any{"roles": after}); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已为用户 %s 授予角色 %s\n", user.Username, role.Name)
+		return nil
+	},
+}
+
+var userBanCmd = &cobra.Command{
+	Use:   "ban <username>",
+	Short: "封禁用户并清除其登录会话",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		user, err := loadUser(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		before := map[string]any{"is_active": user.IsActive, "ban_reason": user.BanReason}
+		if err := adminuser.Ban(ctx, user, banReason); err != nil {
+			if err.Error() == adminuser.BanReasonRequired {
+				return &usageError{err: err}
+			}
+			return err
+		}
+
+		after := map[string]any{"is_active": user.IsActive, "ban_reason": user.BanReason}
+		if err := writeAuditLog(ctx, cmd, audit.TargetUser, user.ID, before, after); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已封禁用户 %s\n", user.Username)
+		return nil
+	},
+}
+
+// loadUser 按用户名加载用户，指定 --id 时按用户 ID 加载
+func loadUser(ctx context.Context, ref string) (*model.User, error) {
+	var user model.User
+	var err error
+	if userByID {
+		id, errParse := strconv.ParseUint(ref, 10, 64)
+		if errParse != nil {
+			return nil, &usageError{err: fmt.Errorf("无效的用户 ID: %s", ref)}
+		}
+		err = user.GetByID(db.DB(ctx), id)
+	} else {
+		err = db.DB(ctx).Where("username = ?", ref).First(&user).Error
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("用户 %s 不存在", ref)
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func init() {
+	userCmd.PersistentFlags().BoolVar(&userByID, "id", false, "按用户 ID 而不是用户名查找")
+	userGrantAdminCmd.Flags().StringVar(&grantRoleName, "role", model.RoleSuperAdmin, "授予的角色名")
+	userBanCmd.Flags().StringVar(&banReason, "reason", "", "封禁原因")
+	_ = userBanCmd.MarkFlagRequired("reason")
+
+	userCmd.AddCommand(userShowCmd, userGrantAdminCmd, userBanCmd)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:               "webhook",
+	Short:             "商户回调运维",
+	PersistentPreRunE: requireDatabase,
+}
+
+var webhookResendCmd = &cobra.Command{
+	Use:   "resend <order_id>",
+	Short: "重新下发商户异步回调，仅支持已支付的商户订单",
+	Args:  exactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		order, err := loadOrder(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if order.ClientID == "" {
+			return fmt.Errorf("订单 %d 不是商户订单", order.ID)
+		}
+		if order.Status != model.OrderStatusSuccess {
+			return fmt.Errorf("订单 %d 状态为 %s，无需回调", order.ID, order.Status)
+		}
+
+		if err := service.EnqueueMerchantNotify(order.ID, order.ClientID); err != nil {
+			return err
+		}
+
+		if err := writeAuditLog(ctx, cmd, audit.TargetOrder, order.ID, nil, map[string]any{"client_id": order.ClientID, "status": order.Status}); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "已重新下发订单 %d 的商户回调\n", order.ID)
+		return nil
+	},
+}
+
+func init() {
+	webhookCmd.AddCommand(webhookResendCmd)
+}
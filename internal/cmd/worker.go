@@ -24,8 +24,9 @@ import (
 )
 
 var workerCmd = &cobra.Command{
-	Use:   "worker",
-	Short: "CDK Worker",
+	Use:    "worker",
+	Short:  "CDK Worker",
+	PreRun: runMigrate,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Println("[Worker] 启动任务处理服务")
 		if err := worker.StartWorker(); err != nil {
@@ -24,4 +24,5 @@ const (
 	DailyLimitExceeded          = "已超过每日限额"
 	PayKeyIncorrect             = "支付密钥错误"
 	CannotPaySelf               = "不能给自己付款"
+	Busy                        = "操作繁忙，请稍后重试"
 )
@@ -19,6 +19,7 @@ package config
 import (
 	"log"
 	"os"
+	"testing"
 
 	"github.com/spf13/viper"
 )
@@ -38,6 +39,11 @@ func init() {
 
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
+		// 单元测试不依赖配置文件，使用仅含日志级别的默认配置，数据库与 Redis 均不启用
+		if testing.Testing() {
+			Config = &configModel{Log: logConfig{Level: "info"}}
+			return
+		}
 		log.Fatalf("[Config] read config failed: %v\n", err)
 	}
 
@@ -26,6 +26,9 @@ type configModel struct {
 	Worker   workerConfig   `mapstructure:"worker"`
 	LinuxDo  linuxDoConfig  `mapstructure:"linuxdo"`
 	Otel     otelConfig     `mapstructure:"otel"`
+	Export   exportConfig   `mapstructure:"export"`
+	Notify   notifyConfig   `mapstructure:"notify"`
+	Cache    cacheConfig    `mapstructure:"cache"`
 }
 
 // appConfig 应用基本配置
@@ -75,6 +78,8 @@ type databaseConfig struct {
 	StatementCacheCapacity int                     `mapstructure:"statement_cache_capacity"`
 	DefaultQueryExecMode   string                  `mapstructure:"default_query_exec_mode"`
 	Replicas               []databaseReplicaConfig `mapstructure:"replicas"`
+	ReplicaMaxLagSeconds   int                     `mapstructure:"replica_max_lag_seconds"`
+	ReplicaLagCheckSeconds int                     `mapstructure:"replica_lag_check_seconds"`
 }
 
 // databaseReplicaConfig 只读副本配置
@@ -119,10 +124,35 @@ type logConfig struct {
 
 // scheduleConfig 定时任务配置
 type scheduleConfig struct {
-	UserGamificationScoreDispatchIntervalSeconds int    `mapstructure:"user_gamification_score_dispatch_interval_seconds"`
-	UpdateUserGamificationScoresTaskCron         string `mapstructure:"update_user_gamification_scores_task_cron"`
-	DisputeAutoRefundDispatchIntervalSeconds     int    `mapstructure:"dispute_auto_refund_dispatch_interval_seconds"`
-	AutoRefundExpiredDisputesTaskCron            string `mapstructure:"auto_refund_expired_disputes_task_cron"`
+	Timezone                                     string               `mapstructure:"timezone"`
+	Tasks                                        []PeriodicTaskConfig `mapstructure:"tasks"`
+	UserGamificationScoreDispatchIntervalSeconds int                  `mapstructure:"user_gamification_score_dispatch_interval_seconds"`
+	UpdateUserGamificationScoresTaskCron         string               `mapstructure:"update_user_gamification_scores_task_cron"`
+	DisputeAutoRefundDispatchIntervalSeconds     int                  `mapstructure:"dispute_auto_refund_dispatch_interval_seconds"`
+	AutoRefundExpiredDisputesTaskCron            string               `mapstructure:"auto_refund_expired_disputes_task_cron"`
+	RemindExpiringDisputesTaskCron               string               `mapstructure:"remind_expiring_disputes_task_cron"`
+	SweepExpiredOrdersTaskCron                   string               `mapstructure:"sweep_expired_orders_task_cron"`
+	DisputeExpiringRemindHours                   int                  `mapstructure:"dispute_expiring_remind_hours"`
+	RefreshMerchantReputationsTaskCron           string               `mapstructure:"refresh_merchant_reputations_task_cron"`
+	AnalyticsRollupTaskCron                      string               `mapstructure:"analytics_rollup_task_cron"`
+	AnalyticsRecomputeDays                       int                  `mapstructure:"analytics_recompute_days"`
+	AnalyticsBackfillBatchDays                   int                  `mapstructure:"analytics_backfill_batch_days"`
+	CleanupExpiredExportsTaskCron                string               `mapstructure:"cleanup_expired_exports_task_cron"`
+	GenerateMonthlyStatementsTaskCron            string               `mapstructure:"generate_monthly_statements_task_cron"`
+	ManageOrderPartitionsTaskCron                string               `mapstructure:"manage_order_partitions_task_cron"`
+	OrderPartitionPremakeMonths                  int                  `mapstructure:"order_partition_premake_months"`
+	ArchiveOrdersTaskCron                        string               `mapstructure:"archive_orders_task_cron"`
+	OrderArchiveAfterDays                        int                  `mapstructure:"order_archive_after_days"`
+	OrderArchiveBatchSize                        int                  `mapstructure:"order_archive_batch_size"`
+}
+
+// PeriodicTaskConfig 周期任务覆盖配置，按任务名覆盖内置定义，未填写的字段保持默认
+type PeriodicTaskConfig struct {
+	Name          string `mapstructure:"name"`
+	Cron          string `mapstructure:"cron"`
+	Queue         string `mapstructure:"queue"`
+	UniqueSeconds int    `mapstructure:"unique_seconds"`
+	Enabled       *bool  `mapstructure:"enabled"`
 }
 
 // workerConfig 工作配置
@@ -138,12 +168,50 @@ type QueueConfig struct {
 	Priority int    `mapstructure:"priority"`
 }
 
+// cacheConfig 进程内缓存配置
+type cacheConfig struct {
+	UserTTLSeconds    int `mapstructure:"user_ttl_seconds"`
+	PayTierTTLSeconds int `mapstructure:"pay_tier_ttl_seconds"`
+}
+
 // linuxDoConfig
 type linuxDoConfig struct {
-	ApiKey string `mapstructure:"api_key"`
+	ApiKey      string `mapstructure:"api_key"`
+	ApiUsername string `mapstructure:"api_username"`
+	BaseURL     string `mapstructure:"base_url"`
 }
 
 // otelConfig OpenTelemetry 配置
 type otelConfig struct {
 	SamplingRate float64 `mapstructure:"sampling_rate"`
 }
+
+// exportConfig 交易导出配置
+type exportConfig struct {
+	SignSecret            string `mapstructure:"sign_secret"`
+	DownloadURLTTLSeconds int    `mapstructure:"download_url_ttl_seconds"`
+	FileRetentionHours    int    `mapstructure:"file_retention_hours"`
+	MaxRangeDays          int    `mapstructure:"max_range_days"`
+	MaxRows               int    `mapstructure:"max_rows"`
+	BeancountCommodity    string `mapstructure:"beancount_commodity"`
+	BeancountAccount      string `mapstructure:"beancount_account"`
+}
+
+// notifyConfig 站外通知渠道配置
+type notifyConfig struct {
+	DispatchDelaySeconds  int        `mapstructure:"dispatch_delay_seconds"`
+	MaxRetry              int        `mapstructure:"max_retry"`
+	RequestTimeoutSeconds int        `mapstructure:"request_timeout_seconds"`
+	WebhookAllowedCIDRs   []string   `mapstructure:"webhook_allowed_cidrs"`
+	SMTP                  smtpConfig `mapstructure:"smtp"`
+}
+
+// smtpConfig SMTP 邮件配置，host 为空时不启用邮件渠道
+type smtpConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	TLS      bool   `mapstructure:"tls"`
+}
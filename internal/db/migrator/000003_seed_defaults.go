@@ -0,0 +1,147 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 迁移中直接按表名写入，避免模型后续增加字段时影响历史迁移
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "seed_defaults",
+		Up:      seedDefaults,
+		// 种子数据上线后会被管理员修改或被业务数据引用，回滚时无法区分，不提供 Down
+	})
+}
+
+// seedDefaults 初始化系统配置、用户支付配置与内置角色，已存在的记录不会被覆盖
+func seedDefaults(tx *gorm.DB) error {
+	now := time.Now()
+
+	systemConfigs := []map[string]interface{}{
+		{"key": model.ConfigKeyMerchantOrderExpireMinutes, "value": "5", "description": "商家订单过期时间（分钟）"},
+		{"key": model.ConfigKeyWebsiteOrderExpireMinutes, "value": "10", "description": "网站订单过期时间（分钟）"},
+		{"key": model.ConfigKeyDisputeTimeWindowHours, "value": "168", "description": "商家争议时间窗口（小时）"},
+		{"key": model.ConfigKeyAdjustmentApprovalThreshold, "value": "1000", "description": "余额调整需二次审批的金额阈值"},
+	}
+	for _, row := range systemConfigs {
+		row["created_at"] = now
+		row["updated_at"] = now
+	}
+	if err := tx.Table("system_configs").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&systemConfigs).Error; err != nil {
+		return err
+	}
+
+	payConfigs := []map[string]interface{}{
+		{"level": model.PayLevelFree, "min_score": 0, "max_score": 2000, "daily_limit": 1000},
+		{"level": model.PayLevelBasic, "min_score": 2000, "max_score": 10000, "daily_limit": 6000},
+		{"level": model.PayLevelStandard, "min_score": 10000, "max_score": 50000, "daily_limit": 25000},
+		{"level": model.PayLevelPremium, "min_score": 50000, "max_score": nil, "daily_limit": nil},
+	}
+	for _, row := range payConfigs {
+		row["fee_rate"] = 0
+		row["score_rate"] = 0
+		row["created_at"] = now
+		row["updated_at"] = now
+	}
+	if err := tx.Table("user_pay_configs").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "level"}}, DoNothing: true}).
+		Create(&payConfigs).Error; err != nil {
+		return err
+	}
+
+	roles := []map[string]interface{}{
+		{
+			"name":        model.RoleSuperAdmin,
+			"description": "超级管理员，拥有全部权限",
+			"permissions": util.StringArray{model.PermissionAll},
+		},
+		{
+			"name":        model.RoleFinance,
+			"description": "财务，负责余额调整与支付配置",
+			"permissions": util.StringArray{
+				model.PermissionUserRead,
+				model.PermissionUserPayConfigRead,
+				model.PermissionUserPayConfigWrite,
+				model.PermissionBalanceAdjustmentRead,
+				model.PermissionBalanceAdjustmentWrite,
+				model.PermissionBalanceAdjustmentReview,
+				model.PermissionAuditLogRead,
+				model.PermissionStatsRead,
+			},
+		},
+		{
+			"name":        model.RoleSupport,
+			"description": "客服，负责用户查询与封禁",
+			"permissions": util.StringArray{
+				model.PermissionUserRead,
+				model.PermissionUserWrite,
+				model.PermissionBalanceAdjustmentRead,
+				model.PermissionMerchantReputationRead,
+			},
+		},
+		{
+			"name":        model.RoleDisputeArbiter,
+			"description": "争议仲裁，负责商户信誉与争议相关事务",
+			"permissions": util.StringArray{
+				model.PermissionUserRead,
+				model.PermissionMerchantReputationRead,
+				model.PermissionMerchantReputationWrite,
+			},
+		},
+		{
+			"name":        model.RoleReadOnly,
+			"description": "只读，可查看所有管理数据",
+			"permissions": util.StringArray{
+				model.PermissionSystemConfigRead,
+				model.PermissionUserPayConfigRead,
+				model.PermissionUserRead,
+				model.PermissionRoleRead,
+				model.PermissionBalanceAdjustmentRead,
+				model.PermissionMerchantReputationRead,
+				model.PermissionAuditLogRead,
+				model.PermissionStatsRead,
+				model.PermissionScheduleRead,
+				model.PermissionTaskQueueRead,
+			},
+		},
+	}
+	for _, row := range roles {
+		row["is_system"] = true
+		row["created_at"] = now
+		row["updated_at"] = now
+	}
+	if err := tx.Table("roles").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&roles).Error; err != nil {
+		return err
+	}
+
+	// 为仅有 IsAdmin 标记的历史管理员分配超级管理员角色
+	return tx.Exec(`INSERT INTO user_roles (user_id, role_id, granted_by, created_at)
+		SELECT users.id, roles.id, 0, NOW() FROM users JOIN roles ON roles.name = ?
+		WHERE users.is_admin AND NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)`, model.RoleSuperAdmin).Error
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// searchIndexes 订单关键字搜索使用的 pg_trgm GIN 索引
+var searchIndexes = []struct {
+	name       string
+	definition string
+}{
+	{"idx_orders_order_name_trgm", "orders USING gin (order_name gin_trgm_ops)"},
+	{"idx_orders_remark_trgm", "orders USING gin (remark gin_trgm_ops)"},
+	{"idx_orders_merchant_order_no_trgm", "orders USING gin (merchant_order_no gin_trgm_ops)"},
+	{"idx_users_username_trgm", "users USING gin (username gin_trgm_ops)"},
+}
+
+func init() {
+	register(Migration{
+		Version:       4,
+		Name:          "search_indexes",
+		Up:            createSearchIndexes,
+		Down:          dropSearchIndexes,
+		NoTransaction: true,
+	})
+}
+
+// createSearchIndexes 并发创建三元组索引，不阻塞订单写入
+// 三元组按字符切分，对中文等无空格分词的文本同样有效；扩展不可用时迁移失败并保持待执行，需由 DBA 安装扩展后重试
+func createSearchIndexes(tx *gorm.DB) error {
+	if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return fmt.Errorf("create extension pg_trgm failed: %w", err)
+	}
+
+	for _, index := range searchIndexes {
+		if err := tx.Exec("CREATE INDEX CONCURRENTLY IF NOT EXISTS " + index.name + " ON " + index.definition).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropSearchIndexes(tx *gorm.DB) error {
+	for _, index := range searchIndexes {
+		if err := tx.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + index.name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
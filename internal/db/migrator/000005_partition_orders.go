@@ -0,0 +1,137 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+)
+
+// orderPartitionPremakeMonths 迁移时预建的未来月份数，之后由定时任务滚动创建
+const orderPartitionPremakeMonths = 3
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "partition_orders",
+		Up:      partitionOrders,
+		Down:    unpartitionOrders,
+	})
+}
+
+// orderIndex pg_indexes 中记录的 orders 索引定义
+type orderIndex struct {
+	Indexname string
+	Indexdef  string
+}
+
+// loadOrderIndexes 读取 orders 上除主键外的索引定义，表重建后按原定义重放
+func loadOrderIndexes(tx *gorm.DB) ([]orderIndex, error) {
+	var indexes []orderIndex
+	err := tx.Raw(`SELECT indexname, indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = 'orders' AND indexname <> 'orders_pkey'
+		ORDER BY indexname`).Scan(&indexes).Error
+	return indexes, err
+}
+
+// rebuildOrders 以 createSQL 新建 orders_rebuild 表并迁入数据，替换原 orders 表后重放索引
+// 主键序列先解除归属，避免随旧表一并删除
+func rebuildOrders(tx *gorm.DB, createSQL string, primaryKey string, afterCreate func(*gorm.DB) error) error {
+	indexes, err := loadOrderIndexes(tx)
+	if err != nil {
+		return err
+	}
+
+	var sequence string
+	if err := tx.Raw("SELECT pg_get_serial_sequence('orders', 'id')").Scan(&sequence).Error; err != nil {
+		return err
+	}
+
+	statements := []string{
+		"UPDATE orders SET created_at = COALESCE(updated_at, NOW()) WHERE created_at IS NULL",
+		"ALTER SEQUENCE " + sequence + " OWNED BY NONE",
+		"ALTER TABLE orders RENAME TO orders_legacy",
+		"ALTER TABLE orders_legacy RENAME CONSTRAINT orders_pkey TO orders_legacy_pkey",
+		createSQL,
+		"ALTER TABLE orders_rebuild ALTER COLUMN created_at SET NOT NULL",
+		"ALTER TABLE orders_rebuild ADD CONSTRAINT orders_pkey PRIMARY KEY (" + primaryKey + ")",
+		"ALTER TABLE orders_rebuild RENAME TO orders",
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	if afterCreate != nil {
+		if err := afterCreate(tx); err != nil {
+			return err
+		}
+	}
+
+	statements = []string{
+		"INSERT INTO orders SELECT * FROM orders_legacy",
+		"DROP TABLE orders_legacy",
+		"ALTER SEQUENCE " + sequence + " OWNED BY orders.id",
+	}
+	for _, index := range indexes {
+		statements = append(statements, index.Indexdef)
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec("ANALYZE orders").Error
+}
+
+// partitionOrders 将 orders 改为按 created_at 月分区，主键调整为 (id, created_at)
+// 分区覆盖最早订单所在月份至未来 orderPartitionPremakeMonths 个月，另建兜底分区；数据量大时应在维护窗口执行
+func partitionOrders(tx *gorm.DB) error {
+	var oldest *time.Time
+	if err := tx.Raw("SELECT MIN(created_at) FROM orders").Scan(&oldest).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	from := now
+	if oldest != nil && oldest.Before(now) {
+		from = *oldest
+	}
+
+	return rebuildOrders(tx,
+		"CREATE TABLE orders_rebuild (LIKE orders_legacy INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY RANGE (created_at)",
+		"id, created_at",
+		func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE " + model.OrderDefaultPartition + " PARTITION OF orders DEFAULT").Error; err != nil {
+				return err
+			}
+			_, err := model.EnsureOrderPartitions(tx, from, now.AddDate(0, orderPartitionPremakeMonths, 0))
+			return err
+		},
+	)
+}
+
+// unpartitionOrders 将分区表还原为普通表，分区随旧表一并删除
+func unpartitionOrders(tx *gorm.DB) error {
+	return rebuildOrders(tx,
+		"CREATE TABLE orders_rebuild (LIKE orders_legacy INCLUDING DEFAULTS INCLUDING CONSTRAINTS)",
+		"id",
+		nil,
+	)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+)
+
+const orderFeeBackfillBatchSize = 5000
+
+// orderFeeRemarkPattern 支付时追加在订单备注末尾的手续费说明，费率精度为整百分比，与实际扣费一致
+const orderFeeRemarkPattern = `\[系统\]: 收取商家(\d+)%手续费$`
+
+func init() {
+	register(Migration{
+		Version:       9,
+		Name:          "backfill_order_fees",
+		Up:            backfillOrderFees,
+		NoTransaction: true,
+	})
+}
+
+// backfillOrderFees 为 fee 字段上线前已支付的订单按备注中记录的费率补齐手续费，按主键分批提交
+// 只补齐 fee 为 0 的订单，可重复执行；早于本迁移生成的统计与月结单需通过 /admin/stats/rebuild 等方式重新生成
+func backfillOrderFees(tx *gorm.DB) error {
+	types := []model.OrderType{model.OrderTypePayment, model.OrderTypeOnline}
+	statuses := []model.OrderStatus{
+		model.OrderStatusSuccess,
+		model.OrderStatusDisputing,
+		model.OrderStatusRefund,
+		model.OrderStatusRefused,
+	}
+
+	var lastID uint64
+	for {
+		var ids []uint64
+		if err := tx.Raw(`SELECT id FROM orders
+			WHERE id > ? AND fee = 0 AND type IN ? AND status IN ? AND remark ~ ?
+			ORDER BY id LIMIT ?`,
+			lastID, types, statuses, orderFeeRemarkPattern, orderFeeBackfillBatchSize).
+			Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Exec(`UPDATE orders
+			SET fee = ROUND(amount * substring(remark FROM ?)::numeric / 100, 2)
+			WHERE id IN ? AND fee = 0`,
+			orderFeeRemarkPattern, ids).Error; err != nil {
+			return err
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
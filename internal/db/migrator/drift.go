@@ -0,0 +1,162 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/linux-do/pay/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// models 当前代码中的全部模型，仅用于检查数据库结构与模型定义是否一致
+func models() []interface{} {
+	return []interface{}{
+		&model.User{},
+		&model.UserPayConfig{},
+		&model.MerchantAPIKey{},
+		&model.MerchantPaymentLink{},
+		&model.Order{},
+		&model.SystemConfig{},
+		&model.Dispute{},
+		&model.MerchantReputation{},
+		&model.BalanceAdjustment{},
+		&model.AuditLog{},
+		&model.Role{},
+		&model.UserRole{},
+		&model.AnalyticsDailyOrderStat{},
+		&model.AnalyticsDailyPlatformStat{},
+		&model.AnalyticsDailyMerchantStat{},
+		&model.AnalyticsDailyPaymentLinkStat{},
+		&model.TransactionExport{},
+		&model.TransactionExportFile{},
+		&model.MonthlyStatement{},
+		&model.MerchantNotifyLog{},
+		&model.OrderRefund{},
+		&model.Notification{},
+		&model.NotificationPreference{},
+		&model.UserNotificationChannel{},
+	}
+}
+
+// TableStatus 单张表与模型定义的差异
+type TableStatus struct {
+	Table          string   `json:"table"`
+	Exists         bool     `json:"exists"`
+	MissingColumns []string `json:"missing_columns,omitempty"`
+	MissingIndexes []string `json:"missing_indexes,omitempty"`
+}
+
+// Drifted 是否与模型定义不一致
+func (s *TableStatus) Drifted() bool {
+	return !s.Exists || len(s.MissingColumns) > 0 || len(s.MissingIndexes) > 0
+}
+
+// Drift 对比模型定义与数据库现状，列出缺失的表、字段与索引，用于发现修改了模型却遗漏迁移的情况
+// 字段类型变更不在检查范围内
+func Drift(ctx context.Context) ([]TableStatus, error) {
+	tx := primary(ctx)
+	migrator := tx.Migrator()
+
+	statuses := make([]TableStatus, 0, len(models()))
+	for _, value := range models() {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(value); err != nil {
+			return nil, err
+		}
+
+		status := TableStatus{Table: stmt.Schema.Table, Exists: migrator.HasTable(value)}
+		if status.Exists {
+			for _, dbName := range stmt.Schema.DBNames {
+				if !migrator.HasColumn(value, dbName) {
+					status.MissingColumns = append(status.MissingColumns, dbName)
+				}
+			}
+			for _, index := range stmt.Schema.ParseIndexes() {
+				if !migrator.HasIndex(value, index.Name) {
+					status.MissingIndexes = append(status.MissingIndexes, index.Name)
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// DryRun 输出待执行迁移的 SQL 而不修改数据库，Go 迁移输出其生成的语句
+func DryRun(ctx context.Context, w io.Writer) error {
+	statuses, err := Statuses(ctx)
+	if err != nil {
+		return err
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	for i := range migrations {
+		m := &migrations[i]
+		if statuses[i].Applied {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "-- %d_%s (%s)\n", m.Version, m.Name, m.Kind()); err != nil {
+			return err
+		}
+		if m.UpSQL != "" {
+			if _, err := fmt.Fprintln(w, m.UpSQL); err != nil {
+				return err
+			}
+			continue
+		}
+
+		session := primary(ctx).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true, Logger: &sqlPrinter{w: w}})
+		if err := m.Up(session); err != nil {
+			if !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+				return err
+			}
+			// 依赖查询结果生成的语句无法预览，仅输出已生成的部分
+			if _, err := fmt.Fprintln(w, "-- remaining statements depend on query results and cannot be previewed"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqlPrinter DryRun 时输出 Go 迁移生成的 SQL
+type sqlPrinter struct {
+	w io.Writer
+}
+
+func (p *sqlPrinter) LogMode(logger.LogLevel) logger.Interface      { return p }
+func (p *sqlPrinter) Info(context.Context, string, ...interface{})  {}
+func (p *sqlPrinter) Warn(context.Context, string, ...interface{})  {}
+func (p *sqlPrinter) Error(context.Context, string, ...interface{}) {}
+
+func (p *sqlPrinter) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	_, _ = fmt.Fprintln(p.w, sql+";")
+}
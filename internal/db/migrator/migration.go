@@ -0,0 +1,173 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SQL 迁移文件命名为 <版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+// 文件首行包含 NoTransactionDirective 时逐条执行且不包裹事务，用于 CREATE INDEX CONCURRENTLY 等语句
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+const (
+	// NoTransactionDirective SQL 迁移不使用事务的标记
+	NoTransactionDirective = "-- +migrate no-transaction"
+)
+
+// Migration 单个版本化迁移，Up/Down 二选一使用 Go 函数或 SQL 文件
+type Migration struct {
+	Version       int64
+	Name          string
+	Up            func(tx *gorm.DB) error
+	Down          func(tx *gorm.DB) error
+	UpSQL         string
+	DownSQL       string
+	NoTransaction bool
+}
+
+// Kind 迁移类型
+func (m *Migration) Kind() string {
+	if m.UpSQL != "" {
+		return "sql"
+	}
+	return "go"
+}
+
+// Reversible 是否支持回滚
+func (m *Migration) Reversible() bool {
+	return m.Down != nil || m.DownSQL != ""
+}
+
+var goMigrations []Migration
+
+// register 注册 Go 迁移，在各迁移文件的 init 中调用
+func register(m Migration) {
+	goMigrations = append(goMigrations, m)
+}
+
+// Migrations 返回按版本号排序的全部迁移
+func Migrations() ([]Migration, error) {
+	sqlMigrations, err := loadSQLMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	all := append(append([]Migration{}, goMigrations...), sqlMigrations...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+
+	for i := 1; i < len(all); i++ {
+		if all[i].Version == all[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d: %s, %s", all[i].Version, all[i-1].Name, all[i].Name)
+		}
+	}
+	return all, nil
+}
+
+// loadSQLMigrations 读取内嵌的 SQL 迁移文件
+func loadSQLMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		versionPart, migrationName, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+		version, err := strconv.ParseInt(versionPart, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", name, err)
+		}
+
+		content, err := sqlFiles.ReadFile(path.Join("sql", name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: migrationName}
+			byVersion[version] = m
+		} else if m.Name != migrationName {
+			return nil, fmt.Errorf("migration version %d has mismatched names %s and %s", version, m.Name, migrationName)
+		}
+
+		if direction == "up" {
+			m.UpSQL = string(content)
+			m.NoTransaction = strings.HasPrefix(m.UpSQL, NoTransactionDirective)
+		} else {
+			m.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing its up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	return migrations, nil
+}
+
+// splitStatements 按行尾分号拆分 SQL，仅用于不包裹事务的迁移，这类迁移中不应包含函数体等多行复合语句
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
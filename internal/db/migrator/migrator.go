@@ -18,136 +18,350 @@ package migrator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
-
-	"github.com/linux-do/pay/internal/model"
+	"time"
 
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/db"
-	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// SchemaMigrationsTable 记录已执行迁移的表
+	SchemaMigrationsTable = "schema_migrations"
+	// BaselineVersion 基线迁移版本，已由 AutoMigrate 建表的数据库直接记录为已执行
+	BaselineVersion int64 = 1
+	// advisoryLockKey 迁移期间持有的 PostgreSQL advisory lock 键，保证同一时间只有一个进程执行迁移
+	advisoryLockKey int64 = 0x6c64_7061_796d // "ldpaym"
+	// legacyMarkerTable 用于识别 AutoMigrate 时代已建表的数据库
+	legacyMarkerTable = "users"
 )
 
+// baselineTables 基线迁移包含的表，即引入版本化迁移前 AutoMigrate 创建的全部表
+var baselineTables = []string{
+	"users",
+	"user_pay_configs",
+	"merchant_api_keys",
+	"merchant_payment_links",
+	"orders",
+	"system_configs",
+	"disputes",
+}
+
+// ErrIrreversible 迁移不支持回滚
+var ErrIrreversible = errors.New("migration is irreversible")
+
+type schemaMigration struct {
+	Version     int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name        string    `gorm:"size:255;not null"`
+	ExecutionMs int64     `gorm:"not null;default:0"`
+	AppliedAt   time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return SchemaMigrationsTable
+}
+
+// MigrationStatus 单个迁移的执行状态
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Kind      string     `json:"kind"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrate 服务启动时执行全部待执行的迁移并检查表结构与模型是否一致，失败时退出进程
 func Migrate() {
 	if !config.Config.Database.Enabled {
 		return
 	}
 
-	if err := db.DB(context.Background()).AutoMigrate(
-		&model.User{},
-		&model.UserPayConfig{},
-		&model.MerchantAPIKey{},
-		&model.MerchantPaymentLink{},
-		&model.Order{},
-		&model.SystemConfig{},
-		&model.Dispute{},
-	); err != nil {
-		log.Fatalf("[PostgreSQL] auto migrate failed: %v\n", err)
+	ctx := context.Background()
+	applied, err := Up(ctx, 0)
+	if err != nil {
+		log.Fatalf("[PostgreSQL] migrate failed: %v\n", err)
 	}
-	log.Printf("[PostgreSQL] auto migrate success\n")
+	log.Printf("[PostgreSQL] migrate success, %d migrations applied\n", len(applied))
 
-	// 初始化系统配置数据
-	initSystemConfigs()
-
-	// 初始化用户支付配置数据
-	initUserPayConfigs()
+	statuses, err := Drift(ctx)
+	if err != nil {
+		log.Fatalf("[PostgreSQL] schema drift check failed: %v\n", err)
+	}
+	drifted := false
+	for i := range statuses {
+		status := &statuses[i]
+		if !status.Drifted() {
+			continue
+		}
+		drifted = true
+		if !status.Exists {
+			log.Printf("[PostgreSQL] schema drift: table %s is missing\n", status.Table)
+			continue
+		}
+		log.Printf("[PostgreSQL] schema drift: table %s missing columns %v, missing indexes %v\n",
+			status.Table, status.MissingColumns, status.MissingIndexes)
+	}
+	if drifted {
+		log.Fatalf("[PostgreSQL] schema does not match models, add the missing migration or run `migrate drift` for details\n")
+	}
 }
 
-// initSystemConfigs 初始化系统配置数据
-func initSystemConfigs() {
-	tx := db.DB(context.Background())
+// Up 按版本顺序执行待执行的迁移，target 大于 0 时只执行到该版本
+func Up(ctx context.Context, target int64) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
 
-	var count int64
-	if err := tx.Model(&model.SystemConfig{}).Count(&count).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to check system_config table: %v\n", err)
-		return
+	var applied []Migration
+	err = withLock(ctx, func() error {
+		if err := ensureSchemaMigrations(ctx); err != nil {
+			return err
+		}
+		if err := adoptLegacySchema(ctx); err != nil {
+			return err
+		}
+
+		done, err := appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := range migrations {
+			m := &migrations[i]
+			if target > 0 && m.Version > target {
+				break
+			}
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			if err := runUp(ctx, m); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+			}
+			log.Printf("[PostgreSQL] applied migration %d_%s\n", m.Version, m.Name)
+			applied = append(applied, *m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按版本倒序回滚最近执行的 steps 个迁移
+func Down(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]*Migration, len(migrations))
+	for i := range migrations {
+		known[migrations[i].Version] = &migrations[i]
 	}
 
-	if count > 0 {
-		return
+	var reverted []Migration
+	err = withLock(ctx, func() error {
+		if err := ensureSchemaMigrations(ctx); err != nil {
+			return err
+		}
+
+		var records []schemaMigration
+		if err := primary(ctx).Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			m, ok := known[record.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is not known to this build", record.Version, record.Name)
+			}
+			if !m.Reversible() {
+				return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, ErrIrreversible)
+			}
+			if err := runDown(ctx, m); err != nil {
+				return fmt.Errorf("rollback %d_%s failed: %w", m.Version, m.Name, err)
+			}
+			log.Printf("[PostgreSQL] reverted migration %d_%s\n", m.Version, m.Name)
+			reverted = append(reverted, *m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Statuses 列出全部迁移及其执行状态，不修改数据库
+func Statuses(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
 	}
 
-	defaultConfigs := []model.SystemConfig{
-		{
-			Key:         model.ConfigKeyMerchantOrderExpireMinutes,
-			Value:       "5",
-			Description: "商家订单过期时间（分钟）",
-		},
-		{
-			Key:         model.ConfigKeyWebsiteOrderExpireMinutes,
-			Value:       "10",
-			Description: "网站订单过期时间（分钟）",
-		},
-		{
-			Key:         model.ConfigKeyDisputeTimeWindowHours,
-			Value:       "168",
-			Description: "商家争议时间窗口（小时）",
-		},
+	records := make(map[int64]schemaMigration)
+	if primary(ctx).Migrator().HasTable(SchemaMigrationsTable) {
+		var rows []schemaMigration
+		if err := primary(ctx).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			records[row.Version] = row
+		}
 	}
 
-	if err := tx.Create(&defaultConfigs).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to create default system configs: %v\n", err)
-	} else {
-		log.Printf("[PostgreSQL] initialized %d default system configs\n", len(defaultConfigs))
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for i := range migrations {
+		status := MigrationStatus{
+			Version: migrations[i].Version,
+			Name:    migrations[i].Name,
+			Kind:    migrations[i].Kind(),
+		}
+		if record, ok := records[migrations[i].Version]; ok {
+			status.Applied = true
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
 	}
+	return statuses, nil
 }
 
-// int64Ptr 返回 int64 指针
-func int64Ptr(v int64) *int64 {
-	return &v
+// primary 迁移始终在主库上执行
+func primary(ctx context.Context) *gorm.DB {
+	return db.DB(db.WithPrimary(ctx))
 }
 
-// initUserPayConfigs 初始化用户支付配置数据
-func initUserPayConfigs() {
-	tx := db.DB(context.Background())
+// withLock 在独占连接上持有 advisory lock 期间执行 fn，其他进程会阻塞等待当前迁移完成
+func withLock(ctx context.Context, fn func() error) error {
+	sqlDB, err := db.DB(ctx).DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
 
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock failed: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey); err != nil {
+			log.Printf("[PostgreSQL] release migration lock failed: %v\n", err)
+		}
+	}()
+
+	return fn()
+}
+
+func ensureSchemaMigrations(ctx context.Context) error {
+	return primary(ctx).Exec(`CREATE TABLE IF NOT EXISTS ` + SchemaMigrationsTable + ` (
+	version bigint PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	execution_ms bigint NOT NULL DEFAULT 0,
+	applied_at timestamptz NOT NULL
+)`).Error
+}
+
+// adoptLegacySchema 数据库已由 AutoMigrate 建表但尚无迁移记录时，将基线记录为已执行
+// 仅在基线的全部表均已存在时接管，否则返回错误，避免缺表的数据库被误记为已执行基线
+func adoptLegacySchema(ctx context.Context) error {
 	var count int64
-	if err := tx.Model(&model.UserPayConfig{}).Count(&count).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to check user_pay_configs table: %v\n", err)
-		return
+	if err := primary(ctx).Model(&schemaMigration{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 || !primary(ctx).Migrator().HasTable(legacyMarkerTable) {
+		return nil
 	}
 
-	if count > 0 {
-		return
+	var missing []string
+	for _, table := range baselineTables {
+		if !primary(ctx).Migrator().HasTable(table) {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("existing schema is incomplete, baseline tables %v are missing; create them or restore the database before migrating", missing)
+	}
+
+	log.Printf("[PostgreSQL] existing schema detected, marking baseline migration %d as applied\n", BaselineVersion)
+	return primary(ctx).Create(&schemaMigration{
+		Version:   BaselineVersion,
+		Name:      "baseline",
+		AppliedAt: time.Now(),
+	}).Error
+}
+
+func appliedVersions(ctx context.Context) (map[int64]struct{}, error) {
+	var versions []int64
+	if err := primary(ctx).Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]struct{}, len(versions))
+	for _, version := range versions {
+		done[version] = struct{}{}
 	}
+	return done, nil
+}
+
+func runUp(ctx context.Context, m *Migration) error {
+	startedAt := time.Now()
+	record := func(tx *gorm.DB) error {
+		return tx.Create(&schemaMigration{
+			Version:     m.Version,
+			Name:        m.Name,
+			ExecutionMs: time.Since(startedAt).Milliseconds(),
+			AppliedAt:   time.Now(),
+		}).Error
+	}
+
+	if m.NoTransaction {
+		if err := execMigration(primary(ctx), m.Up, m.UpSQL, true); err != nil {
+			return err
+		}
+		return record(primary(ctx))
+	}
+
+	return primary(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := execMigration(tx, m.Up, m.UpSQL, false); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
 
-	defaultConfigs := []model.UserPayConfig{
-		{
-			Level:      model.PayLevelFree,
-			MinScore:   0,
-			MaxScore:   int64Ptr(2000),
-			DailyLimit: int64Ptr(1000),
-			FeeRate:    decimal.Zero,
-			ScoreRate:  decimal.Zero,
-		},
-		{
-			Level:      model.PayLevelBasic,
-			MinScore:   2000,
-			MaxScore:   int64Ptr(10000),
-			DailyLimit: int64Ptr(6000),
-			FeeRate:    decimal.Zero,
-			ScoreRate:  decimal.Zero,
-		},
-		{
-			Level:      model.PayLevelStandard,
-			MinScore:   10000,
-			MaxScore:   int64Ptr(50000),
-			DailyLimit: int64Ptr(25000),
-			FeeRate:    decimal.Zero,
-			ScoreRate:  decimal.Zero,
-		},
-		{
-			Level:      model.PayLevelPremium,
-			MinScore:   50000,
-			MaxScore:   nil,
-			DailyLimit: nil,
-			FeeRate:    decimal.Zero,
-			ScoreRate:  decimal.Zero,
-		},
-	}
-
-	if err := tx.Create(&defaultConfigs).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to create default user pay configs: %v\n", err)
-	} else {
-		log.Printf("[PostgreSQL] initialized %d default user pay configs\n", len(defaultConfigs))
+func runDown(ctx context.Context, m *Migration) error {
+	forget := func(tx *gorm.DB) error {
+		return tx.Where("version = ?", m.Version).Delete(&schemaMigration{}).Error
+	}
+
+	if m.NoTransaction {
+		if err := execMigration(primary(ctx), m.Down, m.DownSQL, true); err != nil {
+			return err
+		}
+		return forget(primary(ctx))
+	}
+
+	return primary(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := execMigration(tx, m.Down, m.DownSQL, false); err != nil {
+			return err
+		}
+		return forget(tx)
+	})
+}
+
+// execMigration 执行 Go 函数或 SQL 内容，不包裹事务的 SQL 需逐条执行
+func execMigration(tx *gorm.DB, fn func(*gorm.DB) error, content string, split bool) error {
+	if fn != nil {
+		return fn(tx)
+	}
+	if !split {
+		return tx.Exec(content).Error
+	}
+	for _, statement := range splitStatements(content) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
 	}
+	return nil
 }
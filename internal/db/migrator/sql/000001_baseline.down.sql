@@ -0,0 +1,9 @@
+-- 回滚基线会删除全部业务表及数据
+
+DROP TABLE IF EXISTS "disputes";
+DROP TABLE IF EXISTS "system_configs";
+DROP TABLE IF EXISTS "orders";
+DROP TABLE IF EXISTS "merchant_payment_links";
+DROP TABLE IF EXISTS "merchant_api_keys";
+DROP TABLE IF EXISTS "user_pay_configs";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,142 @@
+-- 基线表结构，与引入版本化迁移前线上 AutoMigrate 生成的结构一致
+-- 已由 AutoMigrate 建表的数据库会直接记录为已执行，不会重复执行本文件，之后新增的表结构均需通过后续迁移添加
+
+CREATE TABLE "users" (
+    "id" bigserial,
+    "username" varchar(64),
+    "nickname" varchar(100),
+    "avatar_url" varchar(100),
+    "trust_level" smallint,
+    "pay_score" bigint DEFAULT 0,
+    "pay_key" varchar(128),
+    "sign_key" varchar(64) NOT NULL,
+    "total_receive" numeric(20,2) DEFAULT '0',
+    "total_payment" numeric(20,2) DEFAULT '0',
+    "total_transfer" numeric(20,2) DEFAULT '0',
+    "total_community" numeric(20,2) DEFAULT '0',
+    "community_balance" numeric(20,2) DEFAULT '0',
+    "available_balance" numeric(20,2) DEFAULT '0',
+    "is_active" boolean DEFAULT true,
+    "is_admin" boolean DEFAULT false,
+    "last_login_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_updated_at" ON "users" ("updated_at");
+CREATE INDEX IF NOT EXISTS "idx_users_created_at" ON "users" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_users_last_login_at" ON "users" ("last_login_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_sign_key" ON "users" ("sign_key");
+CREATE INDEX IF NOT EXISTS "idx_users_pay_score" ON "users" ("pay_score");
+CREATE INDEX IF NOT EXISTS "idx_users_trust_level" ON "users" ("trust_level");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+
+CREATE TABLE "user_pay_configs" (
+    "id" bigserial,
+    "level" smallint NOT NULL,
+    "min_score" bigint NOT NULL,
+    "max_score" bigint,
+    "daily_limit" bigint,
+    "fee_rate" numeric(3,2) DEFAULT '0',
+    "score_rate" numeric(3,2) DEFAULT '0',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_user_pay_configs_fee_rate" CHECK (fee_rate >= 0 AND fee_rate <= 1),
+    CONSTRAINT "chk_user_pay_configs_score_rate" CHECK (score_rate >= 0 AND score_rate <= 1)
+);
+CREATE INDEX IF NOT EXISTS "idx_score_range" ON "user_pay_configs" ("min_score","max_score");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_pay_configs_level" ON "user_pay_configs" ("level");
+
+CREATE TABLE "merchant_api_keys" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "client_id" varchar(64) NOT NULL,
+    "client_secret" varchar(64) NOT NULL,
+    "app_name" varchar(20) NOT NULL,
+    "app_homepage_url" varchar(100) NOT NULL,
+    "app_description" varchar(100),
+    "redirect_uri" varchar(100),
+    "notify_url" varchar(100) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_api_keys_deleted_at" ON "merchant_api_keys" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_client_credentials" ON "merchant_api_keys" ("client_secret","client_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_api_keys_client_id" ON "merchant_api_keys" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_merchant_api_keys_user_created" ON "merchant_api_keys" ("user_id","created_at");
+
+CREATE TABLE "merchant_payment_links" (
+    "id" bigserial,
+    "merchant_api_key_id" bigint NOT NULL,
+    "token" varchar(64) NOT NULL,
+    "amount" numeric(20,2) NOT NULL,
+    "product_name" varchar(30) NOT NULL,
+    "remark" varchar(100),
+    "created_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_payment_links_deleted_at" ON "merchant_payment_links" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_merchant_payment_links_created_at" ON "merchant_payment_links" ("created_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_merchant_payment_links_token" ON "merchant_payment_links" ("token");
+CREATE INDEX IF NOT EXISTS "idx_merchant_payment_links_merchant_api_key_id" ON "merchant_payment_links" ("merchant_api_key_id");
+
+CREATE TABLE "orders" (
+    "id" bigserial,
+    "order_name" varchar(64) NOT NULL,
+    "merchant_order_no" varchar(64),
+    "client_id" varchar(64),
+    "payer_user_id" bigint,
+    "payee_user_id" bigint,
+    "payer_username" text,
+    "payee_username" text,
+    "amount" numeric(20,2) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "remark" varchar(255),
+    "payment_type" varchar(20),
+    "trade_time" timestamptz,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_amount" ON "orders" ("amount");
+CREATE INDEX IF NOT EXISTS "idx_orders_payee_status_type_created" ON "orders" ("payee_user_id","status","type","created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_payer_status_type_trade" ON "orders" ("payer_user_id","status","type","trade_time");
+CREATE INDEX IF NOT EXISTS "idx_orders_payer_status_type_created" ON "orders" ("payer_user_id","status","type","created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_client_payer" ON "orders" ("client_id","payer_user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_client_payee" ON "orders" ("client_id","payee_user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_client_status_created" ON "orders" ("client_id","status","created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_merchant_order_no" ON "orders" ("merchant_order_no");
+
+CREATE TABLE "system_configs" (
+    "key" varchar(64) NOT NULL,
+    "value" varchar(255) NOT NULL,
+    "description" varchar(255),
+    "updated_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("key")
+);
+
+CREATE TABLE "disputes" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "initiator_user_id" bigint NOT NULL,
+    "reason" varchar(500) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'disputing',
+    "handler_user_id" bigint,
+    "initiator_username" text,
+    "handler_username" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_disputes_handler_user_id" ON "disputes" ("handler_user_id");
+CREATE INDEX IF NOT EXISTS "idx_disputes_status" ON "disputes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_initiator_status_created" ON "disputes" ("initiator_user_id","status","created_at");
+CREATE INDEX IF NOT EXISTS "idx_dispute_order_status" ON "disputes" ("order_id","status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_dispute_order" ON "disputes" ("order_id");
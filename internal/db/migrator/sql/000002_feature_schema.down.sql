@@ -0,0 +1,26 @@
+-- 回滚会删除基线之后新增的表、字段及其数据
+
+DROP TABLE IF EXISTS "user_notification_channels";
+DROP TABLE IF EXISTS "notification_preferences";
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "order_refunds";
+DROP TABLE IF EXISTS "merchant_notify_logs";
+DROP TABLE IF EXISTS "monthly_statements";
+DROP TABLE IF EXISTS "transaction_exports";
+DROP TABLE IF EXISTS "analytics_daily_payment_link_stats";
+DROP TABLE IF EXISTS "analytics_daily_merchant_stats";
+DROP TABLE IF EXISTS "analytics_daily_platform_stats";
+DROP TABLE IF EXISTS "analytics_daily_order_stats";
+DROP TABLE IF EXISTS "user_roles";
+DROP TABLE IF EXISTS "roles";
+DROP TABLE IF EXISTS "audit_logs";
+DROP TABLE IF EXISTS "balance_adjustments";
+DROP TABLE IF EXISTS "merchant_reputations";
+
+DROP FUNCTION IF EXISTS audit_logs_append_only();
+
+DROP INDEX IF EXISTS "idx_orders_payment_link_id";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "payment_link_id";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "fee";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "banned_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "ban_reason";
@@ -0,0 +1,287 @@
+-- 基线之后新增的字段、表与触发器
+-- 引入版本化迁移前部分环境已由 AutoMigrate 建过其中的表，因此全部语句均可重复执行
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "ban_reason" varchar(255);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "banned_at" timestamptz;
+
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "fee" numeric(20,2) NOT NULL DEFAULT '0';
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "payment_link_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_orders_payment_link_id" ON "orders" ("payment_link_id");
+
+CREATE TABLE IF NOT EXISTS "merchant_reputations" (
+    "user_id" bigserial,
+    "username" text,
+    "order_count" bigint NOT NULL DEFAULT 0,
+    "dispute_count" bigint NOT NULL DEFAULT 0,
+    "dispute_lost_count" bigint NOT NULL DEFAULT 0,
+    "refund_count" bigint NOT NULL DEFAULT 0,
+    "auto_refund_count" bigint NOT NULL DEFAULT 0,
+    "handled_dispute_count" bigint NOT NULL DEFAULT 0,
+    "avg_response_seconds" bigint NOT NULL DEFAULT 0,
+    "dispute_rate" numeric(7,4) NOT NULL DEFAULT '0',
+    "refund_rate" numeric(7,4) NOT NULL DEFAULT '0',
+    "trust_badge" varchar(20) NOT NULL DEFAULT 'new',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_reputations_trust_badge" ON "merchant_reputations" ("trust_badge");
+CREATE INDEX IF NOT EXISTS "idx_merchant_reputations_refund_rate" ON "merchant_reputations" ("refund_rate");
+CREATE INDEX IF NOT EXISTS "idx_merchant_reputations_dispute_rate" ON "merchant_reputations" ("dispute_rate");
+
+CREATE TABLE IF NOT EXISTS "balance_adjustments" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "username" text,
+    "direction" varchar(10) NOT NULL,
+    "amount" numeric(20,2) NOT NULL,
+    "reason" varchar(255) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "requester_user_id" bigint NOT NULL,
+    "requester_username" text,
+    "approver_user_id" bigint DEFAULT 0,
+    "approver_username" text,
+    "review_remark" varchar(255),
+    "order_id" bigint,
+    "reviewed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_balance_adjustments_created_at" ON "balance_adjustments" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_balance_adjustments_requester_user_id" ON "balance_adjustments" ("requester_user_id");
+CREATE INDEX IF NOT EXISTS "idx_balance_adjustments_status" ON "balance_adjustments" ("status");
+CREATE INDEX IF NOT EXISTS "idx_balance_adjustments_user_id" ON "balance_adjustments" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" bigserial,
+    "actor_user_id" bigint NOT NULL,
+    "actor_username" varchar(64),
+    "action" varchar(128) NOT NULL,
+    "target_type" varchar(64),
+    "target_id" varchar(64),
+    "before" jsonb,
+    "after" jsonb,
+    "diff" jsonb,
+    "ip" varchar(64),
+    "user_agent" varchar(255),
+    "trace_id" varchar(32),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_trace_id" ON "audit_logs" ("trace_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_target" ON "audit_logs" ("target_type","target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_created" ON "audit_logs" ("actor_user_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "roles" (
+    "id" bigserial,
+    "name" varchar(64) NOT NULL,
+    "description" varchar(255),
+    "permissions" jsonb NOT NULL,
+    "is_system" boolean DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_name" ON "roles" ("name");
+
+CREATE TABLE IF NOT EXISTS "user_roles" (
+    "user_id" bigint,
+    "role_id" bigint,
+    "granted_by" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    PRIMARY KEY ("user_id","role_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_roles_role_id" ON "user_roles" ("role_id");
+
+CREATE TABLE IF NOT EXISTS "analytics_daily_order_stats" (
+    "date" date,
+    "type" varchar(20),
+    "order_count" bigint NOT NULL DEFAULT 0,
+    "settled_count" bigint NOT NULL DEFAULT 0,
+    "settled_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "fee" numeric(20,2) NOT NULL DEFAULT '0',
+    "refund_count" bigint NOT NULL DEFAULT 0,
+    "refund_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "active_payers" bigint NOT NULL DEFAULT 0,
+    "active_payees" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("date","type")
+);
+
+CREATE TABLE IF NOT EXISTS "analytics_daily_platform_stats" (
+    "date" date,
+    "settled_count" bigint NOT NULL DEFAULT 0,
+    "settled_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "fee" numeric(20,2) NOT NULL DEFAULT '0',
+    "active_payers" bigint NOT NULL DEFAULT 0,
+    "new_users" bigint NOT NULL DEFAULT 0,
+    "new_merchants" bigint NOT NULL DEFAULT 0,
+    "dispute_count" bigint NOT NULL DEFAULT 0,
+    "dispute_refund" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("date")
+);
+
+CREATE TABLE IF NOT EXISTS "analytics_daily_merchant_stats" (
+    "date" date,
+    "client_id" varchar(64),
+    "order_count" bigint NOT NULL DEFAULT 0,
+    "pending_count" bigint NOT NULL DEFAULT 0,
+    "expired_count" bigint NOT NULL DEFAULT 0,
+    "failed_count" bigint NOT NULL DEFAULT 0,
+    "settled_count" bigint NOT NULL DEFAULT 0,
+    "settled_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "fee" numeric(20,2) NOT NULL DEFAULT '0',
+    "refund_count" bigint NOT NULL DEFAULT 0,
+    "refund_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "dispute_count" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("date","client_id")
+);
+
+CREATE TABLE IF NOT EXISTS "analytics_daily_payment_link_stats" (
+    "date" date,
+    "payment_link_id" bigint,
+    "client_id" varchar(64),
+    "settled_count" bigint NOT NULL DEFAULT 0,
+    "settled_amount" numeric(20,2) NOT NULL DEFAULT '0',
+    "fee" numeric(20,2) NOT NULL DEFAULT '0',
+    "updated_at" timestamptz,
+    PRIMARY KEY ("date","payment_link_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_analytics_daily_payment_link_stats_client_id" ON "analytics_daily_payment_link_stats" ("client_id");
+
+CREATE TABLE IF NOT EXISTS "transaction_exports" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "scope" varchar(20) NOT NULL,
+    "format" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "order_type" varchar(20),
+    "order_status" varchar(20),
+    "client_id" varchar(64),
+    "keyword" varchar(64),
+    "start_time" timestamptz NOT NULL,
+    "end_time" timestamptz NOT NULL,
+    "row_count" bigint NOT NULL DEFAULT 0,
+    "file_name" varchar(128),
+    "file_size" bigint NOT NULL DEFAULT 0,
+    "error_message" varchar(255),
+    "file_expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transaction_exports_created_at" ON "transaction_exports" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_transaction_exports_file_expires_at" ON "transaction_exports" ("file_expires_at");
+CREATE INDEX IF NOT EXISTS "idx_transaction_exports_client_id" ON "transaction_exports" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_transaction_exports_user_status" ON "transaction_exports" ("user_id","status");
+
+CREATE TABLE IF NOT EXISTS "monthly_statements" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "month" varchar(7) NOT NULL,
+    "period_start" timestamptz NOT NULL,
+    "period_end" timestamptz NOT NULL,
+    "opening_balance" numeric(20,2) NOT NULL DEFAULT '0',
+    "total_credit" numeric(20,2) NOT NULL DEFAULT '0',
+    "total_debit" numeric(20,2) NOT NULL DEFAULT '0',
+    "total_fee" numeric(20,2) NOT NULL DEFAULT '0',
+    "closing_balance" numeric(20,2) NOT NULL DEFAULT '0',
+    "lines" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_monthly_statements_month" ON "monthly_statements" ("month");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_monthly_statements_user_month" ON "monthly_statements" ("user_id","month");
+
+CREATE TABLE IF NOT EXISTS "merchant_notify_logs" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "client_id" varchar(64) NOT NULL,
+    "notify_url" varchar(255),
+    "attempt" bigint NOT NULL,
+    "success" boolean NOT NULL,
+    "status_code" bigint,
+    "response" varchar(255),
+    "error_message" varchar(255),
+    "duration_ms" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_merchant_notify_logs_order_id" ON "merchant_notify_logs" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "order_refunds" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "client_id" varchar(64),
+    "amount" numeric(20,2) NOT NULL,
+    "source" varchar(20) NOT NULL,
+    "dispute_id" bigint,
+    "operator_user_id" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_order_refunds_client_id" ON "order_refunds" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_order_refunds_order_id" ON "order_refunds" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "category" varchar(20) NOT NULL,
+    "type" varchar(30) NOT NULL,
+    "title" varchar(100) NOT NULL,
+    "content" varchar(500),
+    "order_id" bigint,
+    "dispute_id" bigint,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_dispute_id" ON "notifications" ("dispute_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_order_id" ON "notifications" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_notification_user_read" ON "notifications" ("user_id","read_at");
+CREATE INDEX IF NOT EXISTS "idx_notification_user_created" ON "notifications" ("user_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "user_id" bigint,
+    "category" varchar(20),
+    "enabled" boolean NOT NULL DEFAULT true,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id","category")
+);
+
+CREATE TABLE IF NOT EXISTS "user_notification_channels" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "channel" varchar(20) NOT NULL,
+    "target" varchar(255),
+    "secret" varchar(128),
+    "enabled" boolean NOT NULL DEFAULT true,
+    "categories" jsonb NOT NULL DEFAULT '[]',
+    "last_delivered_at" timestamptz,
+    "last_error" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_notification_channel" ON "user_notification_channels" ("user_id","channel");
+
+-- 审计日志只允许追加
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only_row ON audit_logs;
+CREATE TRIGGER audit_logs_append_only_row BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS audit_logs_append_only_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_append_only_truncate BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
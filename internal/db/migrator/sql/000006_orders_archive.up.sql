@@ -0,0 +1,12 @@
+-- 订单冷数据归档表，包含 orders 的全部列并追加归档时间
+-- 归档任务按 orders 的列名搬迁数据，orders 新增列时需同步为本表添加同名列
+
+CREATE TABLE IF NOT EXISTS "orders_archive" (LIKE "orders" INCLUDING DEFAULTS);
+ALTER TABLE "orders_archive" ALTER COLUMN "id" DROP DEFAULT;
+ALTER TABLE "orders_archive" ADD COLUMN "archived_at" timestamptz NOT NULL DEFAULT NOW();
+ALTER TABLE "orders_archive" ADD CONSTRAINT "orders_archive_pkey" PRIMARY KEY ("id");
+
+CREATE INDEX IF NOT EXISTS "idx_orders_archive_payer_created" ON "orders_archive" ("payer_user_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_archive_payee_created" ON "orders_archive" ("payee_user_id","created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_archive_client_order_no" ON "orders_archive" ("client_id","merchant_order_no");
+CREATE INDEX IF NOT EXISTS "idx_orders_archive_archived_at" ON "orders_archive" ("archived_at");
@@ -0,0 +1,2 @@
+UPDATE "roles" SET "permissions" = "permissions" - 'dispute:read' - 'dispute:write', "updated_at" = now()
+WHERE "name" IN ('dispute_arbiter', 'read_only') AND "is_system";
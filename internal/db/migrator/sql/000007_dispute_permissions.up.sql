@@ -0,0 +1,6 @@
+-- 争议仲裁与只读角色补充争议权限，已存在的内置角色不会被种子迁移更新
+UPDATE "roles" SET "permissions" = "permissions" || '["dispute:read", "dispute:write"]'::jsonb, "updated_at" = now()
+WHERE "name" = 'dispute_arbiter' AND "is_system" AND NOT "permissions" @> '["dispute:write"]';
+
+UPDATE "roles" SET "permissions" = "permissions" || '["dispute:read"]'::jsonb, "updated_at" = now()
+WHERE "name" = 'read_only' AND "is_system" AND NOT "permissions" @> '["dispute:read"]';
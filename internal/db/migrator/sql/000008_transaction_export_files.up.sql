@@ -0,0 +1,7 @@
+-- 导出文件内容存入数据库，API 与 Worker 多实例部署时无需共享存储目录
+CREATE TABLE IF NOT EXISTS "transaction_export_files" (
+    "export_id" bigint NOT NULL,
+    "content" bytea NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("export_id")
+);
@@ -18,6 +18,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net"
 	"net/url"
@@ -79,6 +80,8 @@ func init() {
 
 	if len(dbConfig.Replicas) > 0 {
 		var replicaDialectors []gorm.Dialector
+		var replicaNames []string
+		var replicaProbes []*sql.DB
 		for _, replica := range dbConfig.Replicas {
 			username := replica.Username
 			if username == "" {
@@ -93,11 +96,39 @@ func init() {
 				DSN:                  replicaDSN,
 				PreferSimpleProtocol: dbConfig.PreferSimpleProtocol,
 			}))
+
+			if dbConfig.ReplicaMaxLagSeconds > 0 {
+				probe, errProbe := openReplicaProbe(replicaDSN)
+				if errProbe != nil {
+					log.Fatalf("[PostgreSQL] init replica lag probe failed: %v\n", errProbe)
+				}
+				replicaNames = append(replicaNames, net.JoinHostPort(replica.Host, strconv.Itoa(replica.Port)))
+				replicaProbes = append(replicaProbes, probe)
+			}
+		}
+
+		var policy dbresolver.Policy = dbresolver.RandomPolicy{}
+		if dbConfig.ReplicaMaxLagSeconds > 0 {
+			// 主库连接池追加为最后一个副本，所有副本延迟超限时读请求回退到主库
+			primaryDB, errPrimary := db.DB()
+			if errPrimary != nil {
+				log.Fatalf("[PostgreSQL] load sql db failed: %v\n", errPrimary)
+			}
+			replicaDialectors = append(replicaDialectors, postgres.New(postgres.Config{Conn: primaryDB}))
+
+			monitor := newReplicaLagMonitor(
+				replicaNames,
+				replicaProbes,
+				time.Duration(dbConfig.ReplicaMaxLagSeconds)*time.Second,
+				time.Duration(dbConfig.ReplicaLagCheckSeconds)*time.Second,
+			)
+			go monitor.run()
+			policy = monitor
 		}
 
 		resolver := dbresolver.Register(dbresolver.Config{
 			Replicas: replicaDialectors,
-			Policy:   dbresolver.RandomPolicy{},
+			Policy:   policy,
 		})
 
 		resolver.SetMaxIdleConns(dbConfig.MaxIdleConn).
@@ -126,6 +157,24 @@ func init() {
 
 }
 
+// openReplicaProbe 建立副本延迟检测专用的单连接连接池，不在启动时连接，副本不可达时由检测结果剔除
+func openReplicaProbe(dsn string) (*sql.DB, error) {
+	probe, err := gorm.Open(postgres.New(postgres.Config{DSN: dsn}), &gorm.Config{
+		Logger:               logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := probe.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	return sqlDB, nil
+}
+
 // buildDSN 构建 PostgreSQL DSN
 func buildDSN(host string, port int, username, password string) string {
 	cfg := config.Config.Database
@@ -169,6 +218,7 @@ func buildDSN(host string, port int, username, password string) string {
 	return pqURL.String()
 }
 
+// DB 返回绑定 context 的数据库对象，读请求按 WithPrimary / WithReplica 设置的路由目标选择主库或副本
 func DB(ctx context.Context) *gorm.DB {
-	return db.WithContext(ctx)
+	return applyRoute(db.WithContext(ctx), ctx)
 }
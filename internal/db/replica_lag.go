@@ -0,0 +1,137 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReplicaLagCheckInterval = 2 * time.Second
+	replicaLagCheckTimeout         = time.Second
+)
+
+// replicaLagSQL 查询副本是否仍在接收 WAL 及复制延迟（秒）
+// WAL 已全部回放时视为无延迟，避免主库空闲时误判；但 WAL 接收进程断开后接收位点不再前进，
+// 接收与回放位点相等并不代表已追上主库，因此需同时确认存在正在流复制的 WAL 接收进程
+// 无 pg_read_all_stats 权限时 pg_stat_wal_receiver 仅返回 pid，status 为空，此时以接收进程存在为准
+const replicaLagSQL = `SELECT
+	NOT pg_is_in_recovery() OR EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status IS NULL OR status = 'streaming'),
+	CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// errReplicaNotStreaming 副本没有正在流复制的 WAL 接收进程
+var errReplicaNotStreaming = errors.New("wal receiver is not streaming")
+
+// replicaLagMonitor 定期检测副本复制延迟，延迟超限或检测失败的副本暂停参与读路由
+// 同时作为 dbresolver 的路由策略，连接池列表末尾为主库，所有副本均不可用时回退到主库
+type replicaLagMonitor struct {
+	names    []string
+	probes   []*sql.DB
+	healthy  []atomic.Bool
+	maxLag   time.Duration
+	interval time.Duration
+}
+
+// newReplicaLagMonitor 为每个副本建立单连接的检测连接池，副本初始视为可用
+func newReplicaLagMonitor(names []string, probes []*sql.DB, maxLag, interval time.Duration) *replicaLagMonitor {
+	if interval <= 0 {
+		interval = defaultReplicaLagCheckInterval
+	}
+	m := &replicaLagMonitor{
+		names:    names,
+		probes:   probes,
+		healthy:  make([]atomic.Bool, len(probes)),
+		maxLag:   maxLag,
+		interval: interval,
+	}
+	for i := range m.healthy {
+		m.healthy[i].Store(true)
+	}
+	return m
+}
+
+// Resolve 在可用副本中随机选择，全部不可用时返回末尾的主库连接池
+func (m *replicaLagMonitor) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	candidates := make([]int, 0, len(m.healthy))
+	for i := range m.healthy {
+		if i < len(connPools)-1 && m.healthy[i].Load() {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return connPools[len(connPools)-1]
+	}
+	return connPools[candidates[rand.IntN(len(candidates))]]
+}
+
+// run 按检测间隔持续检测，进程退出前不会返回
+func (m *replicaLagMonitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.checkAll()
+		<-ticker.C
+	}
+}
+
+// checkAll 检测全部副本并在可用状态变化时记录日志
+func (m *replicaLagMonitor) checkAll() {
+	for i, probe := range m.probes {
+		lag, err := m.check(probe)
+		healthy := err == nil && lag <= m.maxLag
+		if m.healthy[i].Swap(healthy) == healthy {
+			continue
+		}
+
+		switch {
+		case err != nil:
+			log.Printf("[PostgreSQL] replica %s removed from rotation: lag check failed: %v\n", m.names[i], err)
+		case !healthy:
+			log.Printf("[PostgreSQL] replica %s removed from rotation: lag %s exceeds %s\n", m.names[i], lag, m.maxLag)
+		default:
+			log.Printf("[PostgreSQL] replica %s back in rotation: lag %s\n", m.names[i], lag)
+		}
+	}
+}
+
+// check 查询单个副本的复制延迟
+func (m *replicaLagMonitor) check(probe *sql.DB) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaLagCheckTimeout)
+	defer cancel()
+
+	var streaming bool
+	var seconds float64
+	if err := probe.QueryRowContext(ctx, replicaLagSQL).Scan(&streaming, &seconds); err != nil {
+		return 0, err
+	}
+	if !streaming {
+		return 0, errReplicaNotStreaming
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
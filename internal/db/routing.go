@@ -0,0 +1,67 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// route 读请求路由目标
+type route int
+
+const (
+	routeDefault route = iota // SELECT 发往副本，其余发往主库
+	routePrimary              // 全部发往主库，用于写后立即读
+	routeReplica              // 读请求（含 WITH 等非 SELECT 开头的只读 SQL）固定发往副本
+)
+
+type routeContextKey struct{}
+
+// WithPrimary 返回强制走主库的 context，用于写后立即读，避免读到副本上的旧数据
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, routePrimary)
+}
+
+// WithReplica 返回读请求走副本的 context，用于可容忍延迟的列表与统计查询
+// 写入仍走主库，但原生 Exec 也会按读请求路由，仅可用于只读流程
+func WithReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, routeReplica)
+}
+
+// routeFromContext 读取 context 上的路由目标
+func routeFromContext(ctx context.Context) route {
+	if r, ok := ctx.Value(routeContextKey{}).(route); ok {
+		return r
+	}
+	return routeDefault
+}
+
+// applyRoute 按 context 上的路由目标为查询追加 dbresolver 子句
+// 追加子句后重新开启 Session，保证返回值与 WithContext 一样可以作为基础查询重复使用
+func applyRoute(tx *gorm.DB, ctx context.Context) *gorm.DB {
+	switch routeFromContext(ctx) {
+	case routePrimary:
+		return tx.Clauses(dbresolver.Write).Session(&gorm.Session{})
+	case routeReplica:
+		return tx.Clauses(dbresolver.Read).Session(&gorm.Session{})
+	default:
+		return tx
+	}
+}
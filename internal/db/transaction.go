@@ -0,0 +1,109 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	// TxMaxAttempts 事务因锁冲突失败时的最大执行次数（含首次）
+	TxMaxAttempts    = 4
+	txRetryBaseDelay = 20 * time.Millisecond
+	txRetryMaxDelay  = 200 * time.Millisecond
+)
+
+// 可重试的 PostgreSQL 错误码
+const (
+	sqlStateLockNotAvailable     = "55P03" // FOR UPDATE NOWAIT 未获取到行锁
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// ErrBusy 事务因锁冲突重试耗尽，调用方应提示用户稍后重试，可用 errors.Is 判断
+var ErrBusy = errors.New(common.Busy)
+
+// busyError 重试耗尽时返回的错误，错误信息可直接展示给用户，Unwrap 保留最后一次的数据库错误
+type busyError struct {
+	cause error
+}
+
+func (e *busyError) Error() string { return common.Busy }
+
+func (e *busyError) Is(target error) bool { return target == ErrBusy }
+
+func (e *busyError) Unwrap() error { return e.cause }
+
+// IsRetryable 判断是否为锁冲突、序列化失败或死锁等可重试的数据库错误
+func IsRetryable(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.SQLState() {
+	case sqlStateLockNotAvailable, sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Transaction 在主库事务中执行 fn，遇到可重试错误时按带抖动的指数退避重新执行整个事务
+// 重试耗尽返回 ErrBusy；fn 可能被执行多次，只适用于在事务内校验状态、重复执行不会重复记账的操作
+func Transaction(ctx context.Context, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	return runTransaction(ctx, TxMaxAttempts, fn, opts...)
+}
+
+// TransactionOnce 与 Transaction 相同但不重试，用于转账等不具备幂等性的写入
+// 重复提交的请求在锁冲突时直接返回 ErrBusy，避免前一个请求提交后再次执行造成重复扣款
+func TransactionOnce(ctx context.Context, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	return runTransaction(ctx, 1, fn, opts...)
+}
+
+func runTransaction(ctx context.Context, maxAttempts int, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	for attempt := 1; ; attempt++ {
+		err := DB(WithPrimary(ctx)).Transaction(fn, opts...)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt >= maxAttempts {
+			logger.WarnF(ctx, "事务锁冲突执行 %d 次后放弃: %v", attempt, err)
+			return &busyError{cause: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return &busyError{cause: err}
+		case <-time.After(txRetryDelay(attempt)):
+		}
+	}
+}
+
+// txRetryDelay 第 attempt 次失败后的等待时间，在指数退避值的 [1/2, 1] 区间内随机
+func txRetryDelay(attempt int) time.Duration {
+	delay := min(txRetryBaseDelay<<(attempt-1), txRetryMaxDelay)
+	half := delay / 2
+	return half + rand.N(half+1)
+}
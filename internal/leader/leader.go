@@ -0,0 +1,185 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultLeaseTTL 租约有效期，leader 异常退出后最多经过该时间由其他实例接管
+	DefaultLeaseTTL = 15 * time.Second
+	// leaseKeyFormat 租约 Key，使用 hash tag 保证租约与 fencing 计数器在 Cluster 的同一槽位
+	leaseKeyFormat = "leader:{%s}"
+	fenceKeyFormat = "leader:{%s}:fence"
+)
+
+// ErrNotLeader 当前实例不再持有租约或 fencing token 已过期
+var ErrNotLeader = errors.New("not leader")
+
+var (
+	// acquireScript 抢占租约成功时递增 fencing token 并返回，失败返回 0
+	acquireScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0`)
+	// renewScript 仅在租约仍属于自己时续期
+	renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0`)
+	// releaseScript 仅在租约仍属于自己时释放
+	releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`)
+	// verifyScript 校验租约持有者和 fencing token 均未变化
+	verifyScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] and redis.call('GET', KEYS[2]) == ARGV[2] then
+	return 1
+end
+return 0`)
+)
+
+// Elector 基于 Redis 租约的 leader 选举
+// 每次当选都会获得单调递增的 fencing token，下游可用 Verify 拒绝过期 leader 的操作
+type Elector struct {
+	name     string
+	identity string
+	leaseKey string
+	fenceKey string
+	ttl      time.Duration
+}
+
+// New 创建选举器，name 相同的实例竞争同一个租约
+func New(name string, ttl time.Duration) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	hostname, _ := os.Hostname()
+	return &Elector{
+		name:     name,
+		identity: fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		leaseKey: db.PrefixedKey(fmt.Sprintf(leaseKeyFormat, name)),
+		fenceKey: db.PrefixedKey(fmt.Sprintf(fenceKeyFormat, name)),
+		ttl:      ttl,
+	}
+}
+
+// Run 参与选举直到 ctx 取消
+// 当选后以 leader 上下文调用 onElected，失去租约或 ctx 取消时该上下文被取消；
+// onElected 返回后才会释放租约，保证新 leader 接管时旧 leader 已停止工作
+func (e *Elector) Run(ctx context.Context, onElected func(ctx context.Context, fence int64)) {
+	interval := e.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fence, err := e.acquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[Leader] %s 抢占租约失败: %v\n", e.name, err)
+		}
+		if fence > 0 {
+			log.Printf("[Leader] %s 当选 leader: identity=%s fence=%d\n", e.name, e.identity, fence)
+			e.lead(ctx, fence, interval, onElected)
+			log.Printf("[Leader] %s 卸任 leader: fence=%d\n", e.name, fence)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead 执行 leader 任务并定期续约，返回前释放租约
+func (e *Elector) lead(ctx context.Context, fence int64, interval time.Duration, onElected func(ctx context.Context, fence int64)) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		onElected(leaderCtx, fence)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+renew:
+	for {
+		select {
+		case <-ctx.Done():
+			break renew
+		case <-done:
+			break renew
+		case <-ticker.C:
+			if err := e.renew(ctx); err != nil {
+				log.Printf("[Leader] %s 续约失败，放弃 leader: %v\n", e.name, err)
+				break renew
+			}
+		}
+	}
+
+	cancel()
+	<-done
+
+	// 父上下文可能已取消，释放租约使用独立的短超时上下文
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), interval)
+	defer releaseCancel()
+	if err := releaseScript.Run(releaseCtx, db.Redis, []string{e.leaseKey}, e.identity).Err(); err != nil {
+		log.Printf("[Leader] %s 释放租约失败: %v\n", e.name, err)
+	}
+}
+
+func (e *Elector) acquire(ctx context.Context) (int64, error) {
+	return acquireScript.Run(ctx, db.Redis, []string{e.leaseKey, e.fenceKey}, e.identity, e.ttl.Milliseconds()).Int64()
+}
+
+func (e *Elector) renew(ctx context.Context) error {
+	renewed, err := renewScript.Run(ctx, db.Redis, []string{e.leaseKey}, e.identity, e.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if renewed == 0 {
+		return ErrNotLeader
+	}
+	return nil
+}
+
+// Verify 校验当前实例仍是持有该 fencing token 的 leader
+func (e *Elector) Verify(ctx context.Context, fence int64) error {
+	valid, err := verifyScript.Run(ctx, db.Redis, []string{e.leaseKey, e.fenceKey}, e.identity, fence).Int64()
+	if err != nil {
+		return err
+	}
+	if valid == 0 {
+		return ErrNotLeader
+	}
+	return nil
+}
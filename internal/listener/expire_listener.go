@@ -34,21 +34,34 @@ import (
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
-	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/redis/go-redis/v9"
 )
 
-// orderExpireKeyPrefix 订单过期 Key 前缀
-const orderExpireKeyPrefix = "payment:order:expire:"
+const (
+	// ElectionName 过期监听器的 leader 选举名称
+	ElectionName = "expire-listener"
+	// orderExpireKeyPrefix 订单过期 Key 前缀
+	orderExpireKeyPrefix = "payment:order:expire:"
+	// expireBatchSize 启动时过期订单的单批数量
+	expireBatchSize = 500
+)
+
+// Guard 处理事件前的 leader 校验，返回错误时跳过该事件
+type Guard func(ctx context.Context) error
 
-// StartExpireListener 启动过期监听器
-func StartExpireListener(ctx context.Context) error {
+// StartExpireListener 启动过期监听器，应仅在 leader 上运行，ctx 取消后停止监听
+func StartExpireListener(ctx context.Context, guard Guard) error {
 	if db.Redis == nil {
 		return fmt.Errorf("redis client is not initialized")
 	}
 
 	// 初始化时先处理已过期的订单
-	model.ExpirePendingOrders(ctx)
+	if count, err := service.ExpireDueOrders(ctx, expireBatchSize); err != nil {
+		logger.ErrorF(ctx, "过期 pending 订单失败: %v", err)
+	} else {
+		logger.InfoF(ctx, "已将 %d 个已过期的 pending 订单设置为 expired", count)
+	}
 
 	cfg := config.Config.Redis
 
@@ -58,15 +71,15 @@ func StartExpireListener(ctx context.Context) error {
 		if !ok {
 			return fmt.Errorf("redis client is not a ClusterClient")
 		}
-		return startClusterExpireListener(ctx, clusterClient)
+		return startClusterExpireListener(ctx, clusterClient, guard)
 	}
 
 	// Standalone/Sentinel 模式
-	return startStandaloneExpireListener(ctx, cfg.DB)
+	return startStandaloneExpireListener(ctx, cfg.DB, guard)
 }
 
 // startStandaloneExpireListener Standalone/Sentinel 模式的过期监听
-func startStandaloneExpireListener(ctx context.Context, dbIndex int) error {
+func startStandaloneExpireListener(ctx context.Context, dbIndex int, guard Guard) error {
 	// 确保 Redis 开启 keyspace notifications
 	configResult := db.Redis.ConfigSet(ctx, "notify-keyspace-events", "Ex")
 	if configResult.Err() != nil {
@@ -79,14 +92,14 @@ func startStandaloneExpireListener(ctx context.Context, dbIndex int) error {
 	expiredChannel := fmt.Sprintf("__keyevent@%d__:expired", dbIndex)
 	pubSub := db.Redis.Subscribe(ctx, expiredChannel)
 
-	go subscribeExpireEvents(ctx, pubSub, expiredChannel)
+	go subscribeExpireEvents(ctx, pubSub, expiredChannel, guard)
 
 	return nil
 }
 
 // startClusterExpireListener Cluster 模式的过期监听
 // 需要订阅所有分片节点的过期事件
-func startClusterExpireListener(ctx context.Context, clusterClient *redis.ClusterClient) error {
+func startClusterExpireListener(ctx context.Context, clusterClient *redis.ClusterClient, guard Guard) error {
 	expiredChannel := "__keyevent@0__:expired"
 
 	err := clusterClient.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
@@ -99,7 +112,7 @@ func startClusterExpireListener(ctx context.Context, clusterClient *redis.Cluste
 
 		// 订阅该分片的过期事件
 		pubSub := shard.Subscribe(ctx, expiredChannel)
-		go subscribeExpireEvents(ctx, pubSub, fmt.Sprintf("%s (shard: %s)", expiredChannel, shard.Options().Addr))
+		go subscribeExpireEvents(ctx, pubSub, fmt.Sprintf("%s (shard: %s)", expiredChannel, shard.Options().Addr), guard)
 
 		return nil
 	})
@@ -108,7 +121,7 @@ func startClusterExpireListener(ctx context.Context, clusterClient *redis.Cluste
 }
 
 // subscribeExpireEvents 订阅并处理过期事件
-func subscribeExpireEvents(ctx context.Context, pubSub *redis.PubSub, channelDesc string) {
+func subscribeExpireEvents(ctx context.Context, pubSub *redis.PubSub, channelDesc string, guard Guard) {
 	defer pubSub.Close()
 	log.Printf("[Expire Listener] 过期监听器已启动，监听频道: %s", channelDesc)
 
@@ -124,12 +137,12 @@ func subscribeExpireEvents(ctx context.Context, pubSub *redis.PubSub, channelDes
 		}
 
 		// 处理过期事件
-		handleExpiredKey(ctx, msg.Payload)
+		handleExpiredKey(ctx, msg.Payload, guard)
 	}
 }
 
 // handleExpiredKey 处理过期的 Redis key
-func handleExpiredKey(ctx context.Context, expiredKey string) {
+func handleExpiredKey(ctx context.Context, expiredKey string, guard Guard) {
 	fullPrefix := db.PrefixedKey(orderExpireKeyPrefix)
 
 	// 只处理订单过期相关的 key
@@ -144,14 +157,16 @@ func handleExpiredKey(ctx context.Context, expiredKey string) {
 		return
 	}
 
-	// 更新订单状态为过期
-	result := db.DB(ctx).Model(&model.Order{}).
-		Where("id = ? AND status = ?", orderID, model.OrderStatusPending).
-		Update("status", model.OrderStatusExpired)
+	// fencing 校验：租约已被其他实例接管时不再处理
+	if guard != nil {
+		if err := guard(ctx); err != nil {
+			logger.InfoF(ctx, "当前实例已不是 leader，跳过过期事件: order_id=%d, error=%v", orderID, err)
+			return
+		}
+	}
 
-	if result.Error != nil {
-		logger.ErrorF(ctx, "更新订单状态为过期失败: order_id=%d, error=%v", orderID, result.Error)
-	} else if result.RowsAffected > 0 {
-		logger.InfoF(ctx, "订单已过期: order_id=%d", orderID)
+	// 与到期任务共用过期逻辑，先到者生效
+	if _, err := service.ExpireOrder(ctx, orderID); err != nil {
+		logger.ErrorF(ctx, "更新订单状态为过期失败: order_id=%d, error=%v", orderID, err)
 	}
 }
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AnalyticsDailyOrderStat 按日、按订单类型汇总的交易统计
+type AnalyticsDailyOrderStat struct {
+	Date          time.Time       `json:"date" gorm:"type:date;primaryKey"`
+	Type          OrderType       `json:"type" gorm:"type:varchar(20);primaryKey"`
+	OrderCount    int64           `json:"order_count" gorm:"not null;default:0"`
+	SettledCount  int64           `json:"settled_count" gorm:"not null;default:0"`
+	SettledAmount decimal.Decimal `json:"settled_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Fee           decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
+	RefundCount   int64           `json:"refund_count" gorm:"not null;default:0"`
+	RefundAmount  decimal.Decimal `json:"refund_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	ActivePayers  int64           `json:"active_payers" gorm:"not null;default:0"`
+	ActivePayees  int64           `json:"active_payees" gorm:"not null;default:0"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// AnalyticsDailyPlatformStat 按日汇总的平台整体统计
+type AnalyticsDailyPlatformStat struct {
+	Date          time.Time       `json:"date" gorm:"type:date;primaryKey"`
+	SettledCount  int64           `json:"settled_count" gorm:"not null;default:0"`
+	SettledAmount decimal.Decimal `json:"settled_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Fee           decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
+	ActivePayers  int64           `json:"active_payers" gorm:"not null;default:0"`
+	NewUsers      int64           `json:"new_users" gorm:"not null;default:0"`
+	NewMerchants  int64           `json:"new_merchants" gorm:"not null;default:0"`
+	DisputeCount  int64           `json:"dispute_count" gorm:"not null;default:0"`
+	DisputeRefund int64           `json:"dispute_refund" gorm:"not null;default:0"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// AnalyticsDailyMerchantStat 按日、按商户应用汇总的收款统计
+type AnalyticsDailyMerchantStat struct {
+	Date          time.Time       `json:"date" gorm:"type:date;primaryKey"`
+	ClientID      string          `json:"client_id" gorm:"size:64;primaryKey"`
+	OrderCount    int64           `json:"order_count" gorm:"not null;default:0"`
+	PendingCount  int64           `json:"pending_count" gorm:"not null;default:0"`
+	ExpiredCount  int64           `json:"expired_count" gorm:"not null;default:0"`
+	FailedCount   int64           `json:"failed_count" gorm:"not null;default:0"`
+	SettledCount  int64           `json:"settled_count" gorm:"not null;default:0"`
+	SettledAmount decimal.Decimal `json:"settled_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Fee           decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
+	RefundCount   int64           `json:"refund_count" gorm:"not null;default:0"`
+	RefundAmount  decimal.Decimal `json:"refund_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	DisputeCount  int64           `json:"dispute_count" gorm:"not null;default:0"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// AnalyticsDailyPaymentLinkStat 按日、按支付链接汇总的收款统计
+type AnalyticsDailyPaymentLinkStat struct {
+	Date          time.Time       `json:"date" gorm:"type:date;primaryKey"`
+	PaymentLinkID uint64          `json:"payment_link_id" gorm:"primaryKey"`
+	ClientID      string          `json:"client_id" gorm:"size:64;index"`
+	SettledCount  int64           `json:"settled_count" gorm:"not null;default:0"`
+	SettledAmount decimal.Decimal `json:"settled_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Fee           decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
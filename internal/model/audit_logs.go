@@ -0,0 +1,55 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogImmutable 审计日志只允许追加
+var ErrAuditLogImmutable = errors.New("审计日志不允许修改或删除")
+
+// AuditLog 管理操作及敏感操作的审计日志，只允许追加
+type AuditLog struct {
+	ID            uint64       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorUserID   uint64       `json:"actor_user_id" gorm:"not null;index:idx_audit_logs_actor_created,priority:1"`
+	ActorUsername string       `json:"actor_username" gorm:"size:64"`
+	Action        string       `json:"action" gorm:"size:128;not null;index"`
+	TargetType    string       `json:"target_type" gorm:"size:64;index:idx_audit_logs_target,priority:1"`
+	TargetID      string       `json:"target_id" gorm:"size:64;index:idx_audit_logs_target,priority:2"`
+	Before        util.RawJSON `json:"before" gorm:"type:jsonb"`
+	After         util.RawJSON `json:"after" gorm:"type:jsonb"`
+	Diff          util.RawJSON `json:"diff" gorm:"type:jsonb"`
+	IP            string       `json:"ip" gorm:"size:64"`
+	UserAgent     string       `json:"user_agent" gorm:"size:255"`
+	TraceID       string       `json:"trace_id" gorm:"size:32;index"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime;index;index:idx_audit_logs_actor_created,priority:2"`
+}
+
+// BeforeUpdate 禁止修改审计日志
+func (l *AuditLog) BeforeUpdate(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+// BeforeDelete 禁止删除审计日志
+func (l *AuditLog) BeforeDelete(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
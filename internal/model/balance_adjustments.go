@@ -0,0 +1,64 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type BalanceAdjustmentDirection string
+
+const (
+	BalanceAdjustmentDirectionCredit BalanceAdjustmentDirection = "credit"
+	BalanceAdjustmentDirectionDebit  BalanceAdjustmentDirection = "debit"
+)
+
+type BalanceAdjustmentStatus string
+
+const (
+	BalanceAdjustmentStatusPending  BalanceAdjustmentStatus = "pending"
+	BalanceAdjustmentStatusApproved BalanceAdjustmentStatus = "approved"
+	BalanceAdjustmentStatusRejected BalanceAdjustmentStatus = "rejected"
+)
+
+// BalanceAdjustment 管理员余额调整单，超过审批阈值时需另一名管理员审批后才会入账
+type BalanceAdjustment struct {
+	ID                uint64                     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID            uint64                     `json:"user_id" gorm:"not null;index"`
+	Username          string                     `json:"username" gorm:"->"`
+	Direction         BalanceAdjustmentDirection `json:"direction" gorm:"type:varchar(10);not null"`
+	Amount            decimal.Decimal            `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Reason            string                     `json:"reason" gorm:"size:255;not null"`
+	Status            BalanceAdjustmentStatus    `json:"status" gorm:"type:varchar(20);not null;index"`
+	RequesterUserID   uint64                     `json:"requester_user_id" gorm:"not null;index"`
+	RequesterUsername string                     `json:"requester_username" gorm:"->"`
+	ApproverUserID    uint64                     `json:"approver_user_id" gorm:"default:0"`
+	ApproverUsername  string                     `json:"approver_username" gorm:"->"`
+	ReviewRemark      string                     `json:"review_remark" gorm:"size:255"`
+	OrderID           *uint64                    `json:"order_id"`
+	ReviewedAt        *time.Time                 `json:"reviewed_at"`
+	CreatedAt         time.Time                  `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt         time.Time                  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GetByID 通过 ID 查询余额调整单
+func (a *BalanceAdjustment) GetByID(tx *gorm.DB, id uint64) error {
+	return tx.Where("id = ?", id).First(a).Error
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/linux-do/pay/internal/cache"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUserCacheTTL    = 10 * time.Second
+	defaultPayTierCacheTTL = 5 * time.Minute
+	payTierCacheKey        = "all"
+)
+
+var (
+	// userCache 鉴权中间件使用的启用用户缓存，余额变动、封禁等写入提交后需失效
+	userCache = cache.New[uint64, User]("users", cacheTTL(config.Config.Cache.UserTTLSeconds, defaultUserCacheTTL), cache.Uint64Key)
+	// payTierCache 全部支付等级配置，管理端修改后失效
+	payTierCache = cache.New[string, []UserPayConfig]("user_pay_configs", cacheTTL(config.Config.Cache.PayTierTTLSeconds, defaultPayTierCacheTTL), cache.StringKey)
+)
+
+// cacheTTL 将配置的秒数转换为过期时间，未配置时使用默认值
+func cacheTTL(seconds int, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetCachedActiveUser 读取启用状态的用户，未命中时从主库加载
+// 返回值为缓存副本，修改不会影响缓存
+func GetCachedActiveUser(ctx context.Context, userID uint64) (*User, error) {
+	user, err := userCache.GetOrLoad(userID, func() (User, error) {
+		var user User
+		err := db.DB(db.WithPrimary(ctx)).Where("id = ? AND is_active = ?", userID, true).First(&user).Error
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// InvalidateUserCache 失效用户缓存，应在余额、状态等变更提交后调用
+func InvalidateUserCache(ctx context.Context, userIDs ...uint64) {
+	userCache.Invalidate(ctx, userIDs...)
+}
+
+// InvalidatePayTierCache 失效支付等级配置缓存，应在配置变更提交后调用
+func InvalidatePayTierCache(ctx context.Context) {
+	payTierCache.InvalidateAll(ctx)
+}
+
+// payTiers 读取全部支付等级配置，按 ID 升序
+func payTiers(tx *gorm.DB) ([]UserPayConfig, error) {
+	return payTierCache.GetOrLoad(payTierCacheKey, func() ([]UserPayConfig, error) {
+		var tiers []UserPayConfig
+		err := tx.Session(&gorm.Session{NewDB: true}).Order("id ASC").Find(&tiers).Error
+		return tiers, err
+	})
+}
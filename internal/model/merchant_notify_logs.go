@@ -0,0 +1,36 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+)
+
+// MerchantNotifyLog 商户异步回调记录，每次投递一条
+type MerchantNotifyLog struct {
+	ID           uint64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID      uint64    `json:"order_id" gorm:"not null;index"`
+	ClientID     string    `json:"client_id" gorm:"size:64;not null"`
+	NotifyURL    string    `json:"notify_url" gorm:"size:255"`
+	Attempt      int       `json:"attempt" gorm:"not null"`
+	Success      bool      `json:"success" gorm:"not null"`
+	StatusCode   int       `json:"status_code"`
+	Response     string    `json:"response" gorm:"size:255"`
+	ErrorMessage string    `json:"error_message" gorm:"size:255"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type TrustBadge string
+
+const (
+	TrustBadgeNew     TrustBadge = "new"
+	TrustBadgeTrusted TrustBadge = "trusted"
+	TrustBadgeNormal  TrustBadge = "normal"
+	TrustBadgeRisky   TrustBadge = "risky"
+)
+
+// 信誉评级阈值
+const (
+	TrustBadgeMinOrderCount = 10 // 订单数低于该值视为新商户
+)
+
+var (
+	trustedMaxDisputeRate = decimal.NewFromFloat(0.01)
+	trustedMaxRefundRate  = decimal.NewFromFloat(0.02)
+	riskyMinDisputeRate   = decimal.NewFromFloat(0.05)
+	riskyMinRefundRate    = decimal.NewFromFloat(0.10)
+)
+
+type MerchantReputation struct {
+	UserID              uint64          `json:"user_id" gorm:"primaryKey"`
+	Username            string          `json:"username" gorm:"->"`
+	OrderCount          int64           `json:"order_count" gorm:"not null;default:0"`
+	DisputeCount        int64           `json:"dispute_count" gorm:"not null;default:0"`
+	DisputeLostCount    int64           `json:"dispute_lost_count" gorm:"not null;default:0"`
+	RefundCount         int64           `json:"refund_count" gorm:"not null;default:0"`
+	AutoRefundCount     int64           `json:"auto_refund_count" gorm:"not null;default:0"`
+	HandledDisputeCount int64           `json:"handled_dispute_count" gorm:"not null;default:0"`
+	AvgResponseSeconds  int64           `json:"avg_response_seconds" gorm:"not null;default:0"`
+	DisputeRate         decimal.Decimal `json:"dispute_rate" gorm:"type:numeric(7,4);not null;default:0;index"`
+	RefundRate          decimal.Decimal `json:"refund_rate" gorm:"type:numeric(7,4);not null;default:0;index"`
+	TrustBadge          TrustBadge      `json:"trust_badge" gorm:"type:varchar(20);not null;default:'new';index"`
+	CreatedAt           time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GetByUserID 通过商户用户 ID 查询信誉
+func (r *MerchantReputation) GetByUserID(tx *gorm.DB, userID uint64) error {
+	return tx.Where("user_id = ?", userID).First(r).Error
+}
+
+// Calculate 根据订单和争议统计计算争议率、退款率和信誉徽章
+func (r *MerchantReputation) Calculate() {
+	r.DisputeRate = decimal.Zero
+	r.RefundRate = decimal.Zero
+	if r.OrderCount > 0 {
+		orderCount := decimal.NewFromInt(r.OrderCount)
+		r.DisputeRate = decimal.NewFromInt(r.DisputeCount).Div(orderCount).Round(4)
+		r.RefundRate = decimal.NewFromInt(r.RefundCount).Div(orderCount).Round(4)
+	}
+
+	switch {
+	case r.OrderCount < TrustBadgeMinOrderCount:
+		r.TrustBadge = TrustBadgeNew
+	case r.DisputeRate.GreaterThanOrEqual(riskyMinDisputeRate) || r.RefundRate.GreaterThanOrEqual(riskyMinRefundRate):
+		r.TrustBadge = TrustBadgeRisky
+	case r.DisputeRate.LessThanOrEqual(trustedMaxDisputeRate) && r.RefundRate.LessThanOrEqual(trustedMaxRefundRate):
+		r.TrustBadge = TrustBadgeTrusted
+	default:
+		r.TrustBadge = TrustBadgeNormal
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type StatementDirection string
+
+const (
+	StatementDirectionCredit StatementDirection = "credit"
+	StatementDirectionDebit  StatementDirection = "debit"
+)
+
+// StatementLineTypeRefund 退款冲正行的类型，其余行使用 OrderType
+const StatementLineTypeRefund = "refund"
+
+// StatementLine 月结单中按订单类型和收支方向汇总的一行
+type StatementLine struct {
+	Type      string             `json:"type"`
+	Direction StatementDirection `json:"direction"`
+	Count     int64              `json:"count"`
+	Amount    decimal.Decimal    `json:"amount"`
+	Fee       decimal.Decimal    `json:"fee"`
+}
+
+// StatementLines 月结单明细，以 JSON 存储
+type StatementLines []StatementLine
+
+func (sl *StatementLines) Scan(value interface{}) error {
+	bytesValue, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("invalid value: %v", value)
+	}
+	return json.Unmarshal(bytesValue, sl)
+}
+
+func (sl StatementLines) Value() (driver.Value, error) {
+	return json.Marshal(sl)
+}
+
+// MonthlyStatement 用户月结单快照
+type MonthlyStatement struct {
+	ID             uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         uint64          `json:"user_id" gorm:"not null;uniqueIndex:idx_monthly_statements_user_month,priority:1"`
+	Month          string          `json:"month" gorm:"size:7;not null;uniqueIndex:idx_monthly_statements_user_month,priority:2;index"`
+	PeriodStart    time.Time       `json:"period_start" gorm:"not null"`
+	PeriodEnd      time.Time       `json:"period_end" gorm:"not null"`
+	OpeningBalance decimal.Decimal `json:"opening_balance" gorm:"type:numeric(20,2);not null;default:0"`
+	TotalCredit    decimal.Decimal `json:"total_credit" gorm:"type:numeric(20,2);not null;default:0"`
+	TotalDebit     decimal.Decimal `json:"total_debit" gorm:"type:numeric(20,2);not null;default:0"`
+	TotalFee       decimal.Decimal `json:"total_fee" gorm:"type:numeric(20,2);not null;default:0"`
+	ClosingBalance decimal.Decimal `json:"closing_balance" gorm:"type:numeric(20,2);not null;default:0"`
+	Lines          StatementLines  `json:"lines" gorm:"type:jsonb;not null"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type NotificationChannelType string
+
+const (
+	NotificationChannelEmail     NotificationChannelType = "email"     // SMTP 邮件
+	NotificationChannelWebhook   NotificationChannelType = "webhook"   // 用户自定义 Webhook
+	NotificationChannelDiscourse NotificationChannelType = "discourse" // linux.do 私信
+)
+
+type NotificationCategoryList []NotificationCategory
+
+func (l *NotificationCategoryList) Scan(value interface{}) error {
+	bytesValue, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("invalid value: %v", value)
+	}
+	return json.Unmarshal(bytesValue, l)
+}
+
+func (l NotificationCategoryList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// UserNotificationChannel 用户的站外通知渠道配置，每个用户每种渠道一条
+type UserNotificationChannel struct {
+	ID              uint64                   `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID          uint64                   `json:"user_id" gorm:"not null;uniqueIndex:idx_user_notification_channel,priority:1"`
+	Channel         NotificationChannelType  `json:"channel" gorm:"type:varchar(20);not null;uniqueIndex:idx_user_notification_channel,priority:2"`
+	Target          string                   `json:"target" gorm:"size:255"`
+	Secret          string                   `json:"-" gorm:"size:128"`
+	Enabled         bool                     `json:"enabled" gorm:"not null;default:true"`
+	Categories      NotificationCategoryList `json:"categories" gorm:"type:jsonb;not null;default:'[]'"`
+	LastDeliveredAt *time.Time               `json:"last_delivered_at"`
+	LastError       string                   `json:"last_error" gorm:"size:255"`
+	CreatedAt       time.Time                `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time                `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Accepts 渠道是否接收该分类的通知，未指定分类时接收全部
+func (c *UserNotificationChannel) Accepts(category NotificationCategory) bool {
+	return c.Enabled && (len(c.Categories) == 0 || slices.Contains(c.Categories, category))
+}
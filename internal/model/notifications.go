@@ -0,0 +1,76 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+)
+
+type NotificationCategory string
+
+const (
+	NotificationCategoryPayment  NotificationCategory = "payment"
+	NotificationCategoryTransfer NotificationCategory = "transfer"
+	NotificationCategoryDispute  NotificationCategory = "dispute"
+	NotificationCategoryRefund   NotificationCategory = "refund"
+	NotificationCategoryOrder    NotificationCategory = "order"
+)
+
+// NotificationCategories 全部通知分类
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryPayment,
+	NotificationCategoryTransfer,
+	NotificationCategoryDispute,
+	NotificationCategoryRefund,
+	NotificationCategoryOrder,
+}
+
+type NotificationType string
+
+const (
+	NotificationTypePaymentReceived  NotificationType = "payment_received"  // 商户收到付款
+	NotificationTypeTransferReceived NotificationType = "transfer_received" // 收到转账
+	NotificationTypeDisputeOpened    NotificationType = "dispute_opened"    // 商户收到争议
+	NotificationTypeDisputeRefused   NotificationType = "dispute_refused"   // 争议被商户拒绝
+	NotificationTypeDisputeClosed    NotificationType = "dispute_closed"    // 争议被发起方关闭
+	NotificationTypeDisputeExpiring  NotificationType = "dispute_expiring"  // 争议即将超时自动退款
+	NotificationTypeRefundReceived   NotificationType = "refund_received"   // 付款方收到退款
+	NotificationTypeRefundIssued     NotificationType = "refund_issued"     // 商户被自动退款
+	NotificationTypeOrderExpired     NotificationType = "order_expired"     // 商户订单超时未支付
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint64               `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint64               `json:"user_id" gorm:"not null;index:idx_notification_user_created,priority:1;index:idx_notification_user_read,priority:1"`
+	Category  NotificationCategory `json:"category" gorm:"type:varchar(20);not null"`
+	Type      NotificationType     `json:"type" gorm:"type:varchar(30);not null"`
+	Title     string               `json:"title" gorm:"size:100;not null"`
+	Content   string               `json:"content" gorm:"size:500"`
+	OrderID   *uint64              `json:"order_id" gorm:"index"`
+	DisputeID *uint64              `json:"dispute_id" gorm:"index"`
+	ReadAt    *time.Time           `json:"read_at" gorm:"index:idx_notification_user_read,priority:2"`
+	CreatedAt time.Time            `json:"created_at" gorm:"autoCreateTime;index:idx_notification_user_created,priority:2"`
+}
+
+// NotificationPreference 用户通知偏好，无记录的分类默认开启
+type NotificationPreference struct {
+	UserID    uint64               `json:"-" gorm:"primaryKey"`
+	Category  NotificationCategory `json:"category" gorm:"primaryKey;type:varchar(20)"`
+	Enabled   bool                 `json:"enabled" gorm:"not null;default:true"`
+	UpdatedAt time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
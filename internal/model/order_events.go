@@ -0,0 +1,72 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+)
+
+const (
+	// OrderStatusChannelFormat 订单状态变更的 Redis 发布频道
+	OrderStatusChannelFormat = "order:status:%d"
+	// OrderStatusChannelPattern 订阅全部订单状态变更的频道模式
+	OrderStatusChannelPattern = "order:status:*"
+)
+
+// OrderStatusEvent 订单状态变更事件
+type OrderStatusEvent struct {
+	OrderID   uint64      `json:"order_id"`
+	Status    OrderStatus `json:"status"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// IsFinal 订单是否已处于不会再变化的状态
+func (s OrderStatus) IsFinal() bool {
+	switch s {
+	case OrderStatusExpired, OrderStatusClosed, OrderStatusFailed, OrderStatusRefund, OrderStatusRefused:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderStatusChannel 返回订单状态变更频道名
+func OrderStatusChannel(orderID uint64) string {
+	return db.PrefixedKey(fmt.Sprintf(OrderStatusChannelFormat, orderID))
+}
+
+// PublishOrderStatus 发布订单状态变更，应在业务事务提交后调用，失败仅记录日志
+func PublishOrderStatus(ctx context.Context, orderID uint64, status OrderStatus) {
+	if db.Redis == nil {
+		return
+	}
+
+	payload, _ := json.Marshal(&OrderStatusEvent{
+		OrderID:   orderID,
+		Status:    status,
+		UpdatedAt: time.Now(),
+	})
+	if err := db.Redis.Publish(ctx, OrderStatusChannel(orderID), payload).Err(); err != nil {
+		logger.ErrorF(ctx, "发布订单[ID:%d]状态变更失败: %v", orderID, err)
+	}
+}
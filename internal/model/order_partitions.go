@@ -0,0 +1,73 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OrderPartitionPrefix     = "orders_p"       // 订单月分区表名前缀，后接 YYYYMM
+	OrderDefaultPartition    = "orders_default" // 兜底分区，承接尚未建分区月份的数据
+	OrderArchiveTable        = "orders_archive" // 订单冷数据归档表
+	orderPartitionNameLayout = "200601"
+)
+
+// OrderArchiveStatuses 可归档的订单状态，均为未发生资金变动的终态
+var OrderArchiveStatuses = []OrderStatus{OrderStatusExpired, OrderStatusFailed, OrderStatusClosed}
+
+// OrderPartitionMonth 返回 t 所在月份的 UTC 起始时间，分区边界统一按 UTC 划分
+func OrderPartitionMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// OrderPartitionName 返回 month 所在月份的分区表名
+func OrderPartitionName(month time.Time) string {
+	return OrderPartitionPrefix + OrderPartitionMonth(month).Format(orderPartitionNameLayout)
+}
+
+// EnsureOrderPartitions 确保 [from, to] 覆盖的每个月份都存在分区，返回新建的分区数量
+// 兜底分区中已有对应月份的数据时建分区会失败，需保证分区提前创建
+func EnsureOrderPartitions(tx *gorm.DB, from, to time.Time) (int, error) {
+	created := 0
+	for month := OrderPartitionMonth(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+		name := OrderPartitionName(month)
+
+		var exists bool
+		if err := tx.Raw("SELECT to_regclass(?) IS NOT NULL", name).Scan(&exists).Error; err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		if err := tx.Exec(fmt.Sprintf(
+			"CREATE TABLE %s PARTITION OF orders FOR VALUES FROM ('%s') TO ('%s')",
+			name,
+			month.Format(time.RFC3339),
+			month.AddDate(0, 1, 0).Format(time.RFC3339),
+		)).Error; err != nil {
+			return created, fmt.Errorf("failed to create partition %s: %w", name, err)
+		}
+		created++
+	}
+	return created, nil
+}
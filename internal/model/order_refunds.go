@@ -0,0 +1,44 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RefundSource string
+
+const (
+	RefundSourceMerchantAPI RefundSource = "merchant_api" // 商户通过 api.php 主动退款
+	RefundSourceDispute     RefundSource = "dispute"      // 商户同意争议退款
+	RefundSourceDisputeAuto RefundSource = "dispute_auto" // 争议超时系统自动退款
+	RefundSourceArbitration RefundSource = "arbitration"  // 管理员仲裁退款
+)
+
+// OrderRefund 订单退款记录
+type OrderRefund struct {
+	ID             uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID        uint64          `json:"order_id" gorm:"not null;index"`
+	ClientID       string          `json:"client_id" gorm:"size:64;index"`
+	Amount         decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Source         RefundSource    `json:"source" gorm:"type:varchar(20);not null"`
+	DisputeID      *uint64         `json:"dispute_id"`
+	OperatorUserID uint64          `json:"operator_user_id" gorm:"not null;default:0"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
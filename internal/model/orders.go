@@ -17,12 +17,9 @@ limitations under the License.
 package model
 
 import (
-	"context"
 	"fmt"
 	"time"
 
-	"github.com/linux-do/pay/internal/db"
-	"github.com/linux-do/pay/internal/logger"
 	"github.com/shopspring/decimal"
 
 	"gorm.io/gorm"
@@ -31,11 +28,12 @@ import (
 type OrderType string
 
 const (
-	OrderTypeReceive   OrderType = "receive"
-	OrderTypePayment   OrderType = "payment"
-	OrderTypeTransfer  OrderType = "transfer"
-	OrderTypeCommunity OrderType = "community"
-	OrderTypeOnline    OrderType = "online"
+	OrderTypeReceive    OrderType = "receive"
+	OrderTypePayment    OrderType = "payment"
+	OrderTypeTransfer   OrderType = "transfer"
+	OrderTypeCommunity  OrderType = "community"
+	OrderTypeOnline     OrderType = "online"
+	OrderTypeAdjustment OrderType = "adjustment"
 )
 
 type OrderStatus string
@@ -45,11 +43,16 @@ const (
 	OrderStatusFailed    OrderStatus = "failed"
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusExpired   OrderStatus = "expired"
+	OrderStatusClosed    OrderStatus = "closed" // 商户主动关闭的待支付订单
 	OrderStatusDisputing OrderStatus = "disputing"
 	OrderStatusRefund    OrderStatus = "refund"
 	OrderStatusRefused   OrderStatus = "refused"
 )
 
+// MaxOrderLifetime 订单从创建到过期的最长时间，超过此时间的订单一律不可支付
+// 因此 trade_time - MaxOrderLifetime <= created_at <= trade_time，按交易时间查询时可据此限定 created_at 以裁剪分区
+const MaxOrderLifetime = 24 * time.Hour
+
 type Order struct {
 	ID              uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
 	OrderNo         string          `json:"order_no" gorm:"-"`
@@ -61,10 +64,12 @@ type Order struct {
 	PayerUsername   string          `json:"payer_username" gorm:"->"`
 	PayeeUsername   string          `json:"payee_username" gorm:"->"`
 	Amount          decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null;index"`
+	Fee             decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
 	Status          OrderStatus     `json:"status" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:2;index:idx_orders_payer_status_type_created,priority:2;index:idx_orders_client_status_created,priority:2;index:idx_orders_payer_status_type_trade,priority:2"`
 	Type            OrderType       `json:"type" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:3;index:idx_orders_payer_status_type_created,priority:3;index:idx_orders_payer_status_type_trade,priority:3"`
 	Remark          string          `json:"remark" gorm:"size:255"`
 	PaymentType     string          `json:"payment_type" gorm:"size:20"`
+	PaymentLinkID   *uint64         `json:"payment_link_id" gorm:"index"`
 	TradeTime       time.Time       `json:"trade_time" gorm:"index:idx_orders_payer_status_type_trade,priority:4"`
 	ExpiresAt       time.Time       `json:"expires_at" gorm:"not null"`
 	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_orders_payee_status_type_created,priority:4;index:idx_orders_payer_status_type_created,priority:4;index:idx_orders_client_status_created,priority:3"`
@@ -76,16 +81,3 @@ func (o *Order) AfterFind(*gorm.DB) error {
 	o.OrderNo = fmt.Sprintf("%018d", o.ID)
 	return nil
 }
-
-// ExpirePendingOrders 将已过期且 pending 状态的订单设置为 expired
-func ExpirePendingOrders(ctx context.Context) {
-	result := db.DB(ctx).Model(&Order{}).
-		Where("status = ? AND expires_at <= ?", OrderStatusPending, time.Now()).
-		Update("status", OrderStatusExpired)
-
-	if result.Error != nil {
-		logger.ErrorF(ctx, "过期 pending 订单失败: %v", result.Error)
-	} else {
-		logger.InfoF(ctx, "已将 %d 个已过期的 pending 订单设置为 expired", result.RowsAffected)
-	}
-}
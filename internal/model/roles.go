@@ -0,0 +1,202 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// 权限定义，格式为 资源:操作
+const (
+	PermissionAll                     = "*"
+	PermissionSystemConfigRead        = "system_config:read"
+	PermissionSystemConfigWrite       = "system_config:write"
+	PermissionUserPayConfigRead       = "user_pay_config:read"
+	PermissionUserPayConfigWrite      = "user_pay_config:write"
+	PermissionUserRead                = "user:read"
+	PermissionUserWrite               = "user:write"
+	PermissionRoleRead                = "role:read"
+	PermissionRoleWrite               = "role:write"
+	PermissionBalanceAdjustmentRead   = "balance_adjustment:read"
+	PermissionBalanceAdjustmentWrite  = "balance_adjustment:write"
+	PermissionBalanceAdjustmentReview = "balance_adjustment:review"
+	PermissionMerchantReputationRead  = "merchant_reputation:read"
+	PermissionMerchantReputationWrite = "merchant_reputation:write"
+	PermissionDisputeRead             = "dispute:read"
+	PermissionDisputeWrite            = "dispute:write"
+	PermissionAuditLogRead            = "audit_log:read"
+	PermissionStatsRead               = "stats:read"
+	PermissionStatsWrite              = "stats:write"
+	PermissionScheduleRead            = "schedule:read"
+	PermissionScheduleWrite           = "schedule:write"
+	PermissionTaskQueueRead           = "task_queue:read"
+	PermissionTaskQueueWrite          = "task_queue:write"
+)
+
+// Permissions 所有可分配的权限
+var Permissions = []string{
+	PermissionSystemConfigRead,
+	PermissionSystemConfigWrite,
+	PermissionUserPayConfigRead,
+	PermissionUserPayConfigWrite,
+	PermissionUserRead,
+	PermissionUserWrite,
+	PermissionRoleRead,
+	PermissionRoleWrite,
+	PermissionBalanceAdjustmentRead,
+	PermissionBalanceAdjustmentWrite,
+	PermissionBalanceAdjustmentReview,
+	PermissionMerchantReputationRead,
+	PermissionMerchantReputationWrite,
+	PermissionDisputeRead,
+	PermissionDisputeWrite,
+	PermissionAuditLogRead,
+	PermissionStatsRead,
+	PermissionStatsWrite,
+	PermissionScheduleRead,
+	PermissionScheduleWrite,
+	PermissionTaskQueueRead,
+	PermissionTaskQueueWrite,
+}
+
+// 内置角色
+const (
+	RoleSuperAdmin     = "super_admin"
+	RoleFinance        = "finance"
+	RoleSupport        = "support"
+	RoleDisputeArbiter = "dispute_arbiter"
+	RoleReadOnly       = "read_only"
+)
+
+type Role struct {
+	ID          uint64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string           `json:"name" gorm:"size:64;uniqueIndex;not null"`
+	Description string           `json:"description" gorm:"size:255"`
+	Permissions util.StringArray `json:"permissions" gorm:"type:jsonb;not null"`
+	IsSystem    bool             `json:"is_system" gorm:"default:false"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GetByID 通过 ID 查询角色
+func (r *Role) GetByID(tx *gorm.DB, id uint64) error {
+	return tx.Where("id = ?", id).First(r).Error
+}
+
+// GetByName 通过名称查询角色
+func (r *Role) GetByName(tx *gorm.DB, name string) error {
+	return tx.Where("name = ?", name).First(r).Error
+}
+
+// UserRole 用户与角色的关联
+type UserRole struct {
+	UserID    uint64    `json:"user_id" gorm:"primaryKey"`
+	RoleID    uint64    `json:"role_id" gorm:"primaryKey;index"`
+	GrantedBy uint64    `json:"granted_by" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// GetRolesByUserID 查询用户拥有的角色
+func GetRolesByUserID(tx *gorm.DB, userID uint64) ([]Role, error) {
+	var roles []Role
+	if err := tx.Model(&Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.id ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetPermissionsByUserID 查询用户通过角色获得的全部权限
+func GetPermissionsByUserID(tx *gorm.DB, userID uint64) ([]string, error) {
+	roles, err := GetRolesByUserID(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	permissions := make([]string, 0)
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			if _, ok := seen[permission]; ok {
+				continue
+			}
+			seen[permission] = struct{}{}
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+// HasPermission 判断权限集合是否包含指定权限，支持 * 与 资源:* 通配
+func HasPermission(permissions []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, p := range permissions {
+		if p == PermissionAll || p == permission || p == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversPermissions 判断 permissions 是否覆盖 required 中的全部权限，用于限制管理员只能授予自身已拥有的权限
+// 仅 * 覆盖 *，因此超级管理员权限只能由超级管理员授予
+func CoversPermissions(permissions []string, required []string) bool {
+	for _, permission := range required {
+		if !HasPermission(permissions, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidPermission 判断是否为可分配的权限
+func IsValidPermission(permission string) bool {
+	if permission == PermissionAll {
+		return true
+	}
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	resource, action, _ := strings.Cut(permission, ":")
+	if action == "*" {
+		for _, p := range Permissions {
+			if strings.HasPrefix(p, resource+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SyncAdminFlag 根据是否拥有角色同步用户的 IsAdmin 标记
+func SyncAdminFlag(tx *gorm.DB, userIDs ...uint64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&User{}).
+		Where("id IN ?", userIDs).
+		UpdateColumn("is_admin", gorm.Expr("EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)")).Error
+}
@@ -30,9 +30,10 @@ import (
 
 // 配置键常量 - 所有系统配置的 key 定义
 const (
-	ConfigKeyMerchantOrderExpireMinutes = "merchant_order_expire_minutes" // 商家订单过期时间（分钟）
-	ConfigKeyWebsiteOrderExpireMinutes  = "website_order_expire_minutes"  // 网站订单过期时间（分钟）
-	ConfigKeyDisputeTimeWindowHours     = "dispute_time_window_hours"     // 商家争议时间窗口（小时）
+	ConfigKeyMerchantOrderExpireMinutes  = "merchant_order_expire_minutes" // 商家订单过期时间（分钟）
+	ConfigKeyWebsiteOrderExpireMinutes   = "website_order_expire_minutes"  // 网站订单过期时间（分钟）
+	ConfigKeyDisputeTimeWindowHours      = "dispute_time_window_hours"     // 商家争议时间窗口（小时）
+	ConfigKeyAdjustmentApprovalThreshold = "adjustment_approval_threshold" // 余额调整需二次审批的金额阈值
 )
 
 const (
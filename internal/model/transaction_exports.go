@@ -0,0 +1,75 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV       ExportFormat = "csv"
+	ExportFormatXLSX      ExportFormat = "xlsx"
+	ExportFormatBeancount ExportFormat = "beancount"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"
+	ExportStatusProcessing ExportStatus = "processing"
+	ExportStatusSuccess    ExportStatus = "success"
+	ExportStatusFailed     ExportStatus = "failed"
+	ExportStatusExpired    ExportStatus = "expired"
+)
+
+type ExportScope string
+
+const (
+	ExportScopeUser     ExportScope = "user"     // 用户视角的个人交易
+	ExportScopeMerchant ExportScope = "merchant" // 商户应用下的全部订单
+)
+
+// TransactionExport 交易导出记录
+type TransactionExport struct {
+	ID            uint64       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        uint64       `json:"user_id" gorm:"not null;index:idx_transaction_exports_user_status,priority:1"`
+	Scope         ExportScope  `json:"scope" gorm:"type:varchar(20);not null"`
+	Format        ExportFormat `json:"format" gorm:"type:varchar(20);not null"`
+	Status        ExportStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_transaction_exports_user_status,priority:2"`
+	OrderType     string       `json:"order_type" gorm:"size:20"`
+	OrderStatus   string       `json:"order_status" gorm:"size:20"`
+	ClientID      string       `json:"client_id" gorm:"size:64;index"`
+	Keyword       string       `json:"keyword" gorm:"size:64"`
+	StartTime     time.Time    `json:"start_time" gorm:"not null"`
+	EndTime       time.Time    `json:"end_time" gorm:"not null"`
+	RowCount      int64        `json:"row_count" gorm:"not null;default:0"`
+	FileName      string       `json:"file_name" gorm:"size:128"`
+	FileSize      int64        `json:"file_size" gorm:"not null;default:0"`
+	ErrorMessage  string       `json:"error_message" gorm:"size:255"`
+	FileExpiresAt *time.Time   `json:"file_expires_at" gorm:"index"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TransactionExportFile 导出文件内容，存放在数据库中供 API 与 Worker 的所有实例共享，与导出记录分表避免列表查询读取大字段
+type TransactionExportFile struct {
+	ExportID  uint64    `json:"export_id" gorm:"primaryKey;autoIncrement:false"`
+	Content   []byte    `json:"-" gorm:"type:bytea;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -44,11 +44,19 @@ type UserPayConfig struct {
 	UpdatedAt  time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-// GetByPayScore 通过 pay_score 查询对应的支付配置
+// GetByPayScore 通过 pay_score 查询对应的支付配置，等级配置整体缓存在进程内，未匹配时返回 gorm.ErrRecordNotFound
 func (upc *UserPayConfig) GetByPayScore(tx *gorm.DB, payScore int64) error {
-	return tx.Where("min_score <= ?", payScore).
-		Where("max_score IS NULL OR max_score > ?", payScore).
-		First(upc).Error
+	tiers, err := payTiers(tx)
+	if err != nil {
+		return err
+	}
+	for i := range tiers {
+		if tiers[i].MinScore <= payScore && (tiers[i].MaxScore == nil || *tiers[i].MaxScore > payScore) {
+			*upc = tiers[i]
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
 }
 
 // GetByID 通过 ID 查询支付配置
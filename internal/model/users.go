@@ -80,6 +80,8 @@ type User struct {
 	AvailableBalance decimal.Decimal `json:"available_balance" gorm:"type:numeric(20,2);default:0"`
 	IsActive         bool            `json:"is_active" gorm:"default:true"`
 	IsAdmin          bool            `json:"is_admin" gorm:"default:false"`
+	BanReason        string          `json:"ban_reason" gorm:"size:255"`
+	BannedAt         *time.Time      `json:"banned_at"`
 	LastLoginAt      time.Time       `json:"last_login_at" gorm:"index"`
 	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
 	UpdatedAt        time.Time       `json:"updated_at" gorm:"autoUpdateTime;index"`
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/otel_trace"
 	"go.opentelemetry.io/otel/codes"
@@ -71,3 +72,11 @@ func loggerMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// replicaReadMiddleware 列表类只读接口可容忍复制延迟，读请求固定走副本以减轻主库压力
+func replicaReadMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(db.WithReplica(c.Request.Context()))
+		c.Next()
+	}
+}
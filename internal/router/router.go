@@ -28,10 +28,21 @@ import (
 	"time"
 
 	"github.com/linux-do/pay/internal/apps/admin"
+	"github.com/linux-do/pay/internal/apps/admin/cache_stat"
+	"github.com/linux-do/pay/internal/apps/admin/periodic_task"
+	"github.com/linux-do/pay/internal/apps/admin/task_queue"
 	publicconfig "github.com/linux-do/pay/internal/apps/config"
 	"github.com/linux-do/pay/internal/apps/dispute"
 	"github.com/linux-do/pay/internal/apps/merchant/api_key"
 	"github.com/linux-do/pay/internal/apps/merchant/link"
+	merchantorder "github.com/linux-do/pay/internal/apps/merchant/order"
+	"github.com/linux-do/pay/internal/apps/merchant/reputation"
+	"github.com/linux-do/pay/internal/apps/order/export"
+	"github.com/linux-do/pay/internal/apps/user/notification"
+	"github.com/linux-do/pay/internal/apps/user/statement"
+	"github.com/linux-do/pay/internal/audit"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/leader"
 	"github.com/linux-do/pay/internal/listener"
 
 	"github.com/linux-do/pay/internal/apps/payment"
@@ -40,13 +51,20 @@ import (
 	"github.com/gin-contrib/sessions/redis"
 	"github.com/gin-gonic/gin"
 	_ "github.com/linux-do/pay/docs"
+	"github.com/linux-do/pay/internal/apps/admin/audit_log"
+	"github.com/linux-do/pay/internal/apps/admin/balance_adjustment"
+	"github.com/linux-do/pay/internal/apps/admin/role"
 	"github.com/linux-do/pay/internal/apps/admin/system_config"
+	adminuser "github.com/linux-do/pay/internal/apps/admin/user"
 	"github.com/linux-do/pay/internal/apps/admin/user_pay_config"
+	"github.com/linux-do/pay/internal/apps/analytics"
 	"github.com/linux-do/pay/internal/apps/health"
 	"github.com/linux-do/pay/internal/apps/oauth"
 	"github.com/linux-do/pay/internal/apps/order"
 	"github.com/linux-do/pay/internal/apps/user"
+	"github.com/linux-do/pay/internal/cache"
 	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/otel_trace"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -70,6 +88,10 @@ func Serve() {
 		sessionAddr = addrs[0]
 	}
 
+	if err := export.ValidateSignSecret(); err != nil {
+		log.Fatalf("[API] invalid export config: %v\n", err)
+	}
+
 	sessionStore, err := redis.NewStoreWithDB(
 		cfg.MinIdleConn,
 		"tcp",
@@ -85,7 +107,7 @@ func Serve() {
 
 	// 设置 Session Redis Key 前缀
 	if cfg.KeyPrefix != "" {
-		if err := redis.SetKeyPrefix(sessionStore, cfg.KeyPrefix+"session:"); err != nil {
+		if err := redis.SetKeyPrefix(sessionStore, oauth.SessionKeyPrefix()); err != nil {
 			log.Printf("[API] set session key prefix failed: %v\n", err)
 		}
 	}
@@ -135,17 +157,34 @@ func Serve() {
 			userRouter := apiV1Router.Group("/user")
 			userRouter.Use(oauth.LoginRequired())
 			{
-				userRouter.PUT("/pay-key", user.UpdatePayKey)
+				userRouter.PUT("/pay-key", audit.Middleware(), user.UpdatePayKey)
+				userRouter.GET("/statements", replicaReadMiddleware(), statement.ListStatements)
+				userRouter.GET("/statements/:month", statement.GetStatement)
+				userRouter.GET("/notifications", replicaReadMiddleware(), notification.ListNotifications)
+				userRouter.GET("/notifications/unread-count", notification.GetUnreadCount)
+				userRouter.POST("/notifications/read", notification.MarkRead)
+				userRouter.GET("/notifications/preferences", notification.GetPreferences)
+				userRouter.PUT("/notifications/preferences", notification.UpdatePreferences)
+				userRouter.GET("/notification-channels", notification.ListChannels)
+				userRouter.PUT("/notification-channels/:channel", notification.UpsertChannel)
+				userRouter.DELETE("/notification-channels/:channel", notification.DeleteChannel)
+				userRouter.POST("/notification-channels/:channel/test", notification.TestChannel)
 			}
 
+			// Export Download（签名校验，无需登录）
+			apiV1Router.GET("/exports/download", export.DownloadExport)
+
 			// Order
 			orderRouter := apiV1Router.Group("/order")
 			orderRouter.Use(oauth.LoginRequired())
 			{
-				orderRouter.POST("/transactions", order.ListTransactions)
+				orderRouter.POST("/transactions", replicaReadMiddleware(), order.ListTransactions)
+				orderRouter.POST("/exports", export.CreateExport)
+				orderRouter.GET("/exports", export.ListExports)
+				orderRouter.GET("/exports/:id", export.GetExport)
 				orderRouter.POST("/dispute", dispute.CreateDispute)
-				orderRouter.POST("/disputes/merchant", dispute.ListMerchantDisputes)
-				orderRouter.POST("/disputes", dispute.ListDisputes)
+				orderRouter.POST("/disputes/merchant", replicaReadMiddleware(), dispute.ListMerchantDisputes)
+				orderRouter.POST("/disputes", replicaReadMiddleware(), dispute.ListDisputes)
 				orderRouter.POST("/refund-review", dispute.RefundReview)
 				orderRouter.POST("/dispute/close", dispute.CloseDispute)
 			}
@@ -174,7 +213,17 @@ func Serve() {
 				{
 					apiKeyRouter.GET("", api_key.GetAPIKey)
 					apiKeyRouter.PUT("", api_key.UpdateAPIKey)
-					apiKeyRouter.DELETE("", api_key.DeleteAPIKey)
+					apiKeyRouter.DELETE("", audit.Middleware(), api_key.DeleteAPIKey)
+					apiKeyRouter.GET("/stats", analytics.GetMerchantAppStats)
+					apiKeyRouter.POST("/exports", export.CreateMerchantExport)
+
+					// Orders
+					merchantOrderRouter := apiKeyRouter.Group("/orders")
+					{
+						merchantOrderRouter.GET("", replicaReadMiddleware(), merchantorder.ListOrders)
+						merchantOrderRouter.GET("/:orderId", merchantorder.RequireOrder(), merchantorder.GetOrder)
+						merchantOrderRouter.POST("/:orderId/close", audit.Middleware(), merchantorder.RequireOrder(), merchantorder.CloseOrder)
+					}
 
 					// Payment Links
 					linkRouter := apiKeyRouter.Group("/payment-links")
@@ -192,34 +241,107 @@ func Serve() {
 				MerchantPaymentRouter := merchantRouter.Group("/payment")
 				{
 					MerchantPaymentRouter.GET("/order", oauth.LoginRequired(), payment.GetPaymentPageDetails)
+					MerchantPaymentRouter.GET("/order/events", oauth.LoginRequired(), payment.StreamPaymentPageStatus)
+					MerchantPaymentRouter.GET("/events", payment.StreamMerchantOrderStatus)
 					MerchantPaymentRouter.POST("", oauth.LoginRequired(), payment.PayMerchantOrder)
 				}
 			}
 
 			// Admin
 			adminRouter := apiV1Router.Group("/admin")
-			adminRouter.Use(oauth.LoginRequired(), admin.LoginAdminRequired())
+			adminRouter.Use(oauth.LoginRequired(), admin.LoginAdminRequired(), audit.Middleware())
 			{
 				// System Config
-				adminRouter.POST("/system-configs", system_config.CreateSystemConfig)
-				adminRouter.GET("/system-configs", system_config.ListSystemConfigs)
+				adminRouter.POST("/system-configs", admin.RequirePermission(model.PermissionSystemConfigWrite), system_config.CreateSystemConfig)
+				adminRouter.GET("/system-configs", admin.RequirePermission(model.PermissionSystemConfigRead), system_config.ListSystemConfigs)
 
 				systemConfigRouter := adminRouter.Group("/system-configs/:key")
 				{
-					systemConfigRouter.GET("", system_config.GetSystemConfig)
-					systemConfigRouter.PUT("", system_config.UpdateSystemConfig)
-					systemConfigRouter.DELETE("", system_config.DeleteSystemConfig)
+					systemConfigRouter.GET("", admin.RequirePermission(model.PermissionSystemConfigRead), system_config.GetSystemConfig)
+					systemConfigRouter.PUT("", admin.RequirePermission(model.PermissionSystemConfigWrite), system_config.UpdateSystemConfig)
+					systemConfigRouter.DELETE("", admin.RequirePermission(model.PermissionSystemConfigWrite), system_config.DeleteSystemConfig)
 				}
 
 				// User Credit Config
-				adminRouter.POST("/user-pay-configs", user_pay_config.CreateUserPayConfig)
-				adminRouter.GET("/user-pay-configs", user_pay_config.ListUserPayConfigs)
+				adminRouter.POST("/user-pay-configs", admin.RequirePermission(model.PermissionUserPayConfigWrite), user_pay_config.CreateUserPayConfig)
+				adminRouter.GET("/user-pay-configs", admin.RequirePermission(model.PermissionUserPayConfigRead), user_pay_config.ListUserPayConfigs)
 
 				userPayConfigRouter := adminRouter.Group("/user-pay-configs/:id")
 				{
-					userPayConfigRouter.GET("", user_pay_config.GetUserPayConfig)
-					userPayConfigRouter.PUT("", user_pay_config.UpdateUserPayConfig)
-					userPayConfigRouter.DELETE("", user_pay_config.DeleteUserPayConfig)
+					userPayConfigRouter.GET("", admin.RequirePermission(model.PermissionUserPayConfigRead), user_pay_config.GetUserPayConfig)
+					userPayConfigRouter.PUT("", admin.RequirePermission(model.PermissionUserPayConfigWrite), user_pay_config.UpdateUserPayConfig)
+					userPayConfigRouter.DELETE("", admin.RequirePermission(model.PermissionUserPayConfigWrite), user_pay_config.DeleteUserPayConfig)
+				}
+
+				// Merchant Reputation
+				adminRouter.GET("/merchant-reputations", admin.RequirePermission(model.PermissionMerchantReputationRead), reputation.ListReputations)
+				adminRouter.POST("/merchant-reputations/refresh", admin.RequirePermission(model.PermissionMerchantReputationWrite), reputation.RefreshReputations)
+				adminRouter.GET("/merchant-reputations/:user_id", admin.RequirePermission(model.PermissionMerchantReputationRead), reputation.GetReputation)
+
+				// Dispute
+				adminRouter.GET("/disputes", admin.RequirePermission(model.PermissionDisputeRead), replicaReadMiddleware(), dispute.ListAllDisputes)
+				adminRouter.POST("/disputes/:id/arbitrate", admin.RequirePermission(model.PermissionDisputeWrite), dispute.ArbitrateDispute)
+
+				// User Management
+				adminRouter.GET("/users", admin.RequirePermission(model.PermissionUserRead), replicaReadMiddleware(), adminuser.ListUsers)
+
+				// 先校验权限再加载用户，避免无权限的管理员通过 404 与 403 的差异探测用户是否存在
+				adminUserRouter := adminRouter.Group("/users/:id")
+				{
+					adminUserRouter.GET("", admin.RequirePermission(model.PermissionUserRead), adminuser.RequireUser(), adminuser.GetUser)
+					adminUserRouter.POST("/ban", admin.RequirePermission(model.PermissionUserWrite), adminuser.RequireUser(), adminuser.BanUser)
+					adminUserRouter.POST("/unban", admin.RequirePermission(model.PermissionUserWrite), adminuser.RequireUser(), adminuser.UnbanUser)
+					adminUserRouter.GET("/roles", admin.RequirePermission(model.PermissionRoleRead), adminuser.RequireUser(), adminuser.ListUserRoles)
+					adminUserRouter.PUT("/roles", admin.RequirePermission(model.PermissionRoleWrite), adminuser.RequireUser(), adminuser.UpdateUserRoles)
+				}
+
+				// Role
+				adminRouter.GET("/permissions", admin.RequirePermission(model.PermissionRoleRead), role.ListPermissions)
+				adminRouter.GET("/roles", admin.RequirePermission(model.PermissionRoleRead), role.ListRoles)
+				adminRouter.POST("/roles", admin.RequirePermission(model.PermissionRoleWrite), role.CreateRole)
+				adminRouter.PUT("/roles/:id", admin.RequirePermission(model.PermissionRoleWrite), role.UpdateRole)
+				adminRouter.DELETE("/roles/:id", admin.RequirePermission(model.PermissionRoleWrite), role.DeleteRole)
+
+				// Balance Adjustment
+				adminRouter.POST("/balance-adjustments", admin.RequirePermission(model.PermissionBalanceAdjustmentWrite), balance_adjustment.CreateAdjustment)
+				adminRouter.GET("/balance-adjustments", admin.RequirePermission(model.PermissionBalanceAdjustmentRead), replicaReadMiddleware(), balance_adjustment.ListAdjustments)
+				adminRouter.POST("/balance-adjustments/:id/approve", admin.RequirePermission(model.PermissionBalanceAdjustmentReview), balance_adjustment.ApproveAdjustment)
+				adminRouter.POST("/balance-adjustments/:id/reject", admin.RequirePermission(model.PermissionBalanceAdjustmentReview), balance_adjustment.RejectAdjustment)
+
+				// Audit Log
+				adminRouter.GET("/audit-logs", admin.RequirePermission(model.PermissionAuditLogRead), replicaReadMiddleware(), audit_log.ListAuditLogs)
+				adminRouter.GET("/audit-logs/:id", admin.RequirePermission(model.PermissionAuditLogRead), audit_log.GetAuditLog)
+
+				// Stats
+				statsRouter := adminRouter.Group("/stats")
+				{
+					statsRouter.GET("/overview", admin.RequirePermission(model.PermissionStatsRead), analytics.GetOverview)
+					statsRouter.GET("/daily", admin.RequirePermission(model.PermissionStatsRead), analytics.ListDailyStats)
+					statsRouter.GET("/types", admin.RequirePermission(model.PermissionStatsRead), analytics.ListTypeStats)
+					statsRouter.POST("/rebuild", admin.RequirePermission(model.PermissionStatsWrite), analytics.RebuildStats)
+				}
+
+				// Caches
+				adminRouter.GET("/caches", admin.RequirePermission(model.PermissionStatsRead), cache_stat.ListCacheStats)
+
+				// Schedules
+				adminRouter.GET("/schedules", admin.RequirePermission(model.PermissionScheduleRead), periodic_task.ListPeriodicTasks)
+				adminRouter.POST("/schedules/:name/run", admin.RequirePermission(model.PermissionScheduleWrite), periodic_task.RunPeriodicTask)
+
+				// Task queues
+				adminRouter.GET("/tasks/queues", admin.RequirePermission(model.PermissionTaskQueueRead), task_queue.ListQueues)
+				// 先校验权限再加载队列，避免无权限的管理员通过 404 与 403 的差异探测队列是否存在
+				taskQueueRouter := adminRouter.Group("/tasks/queues/:queue")
+				{
+					taskQueueRouter.GET("", admin.RequirePermission(model.PermissionTaskQueueRead), task_queue.RequireQueue(), task_queue.GetQueue)
+					taskQueueRouter.POST("/pause", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.PauseQueue)
+					taskQueueRouter.POST("/resume", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.ResumeQueue)
+					taskQueueRouter.GET("/tasks", admin.RequirePermission(model.PermissionTaskQueueRead), task_queue.RequireQueue(), task_queue.ListTasks)
+					taskQueueRouter.GET("/tasks/:taskId", admin.RequirePermission(model.PermissionTaskQueueRead), task_queue.RequireQueue(), task_queue.GetTask)
+					taskQueueRouter.POST("/tasks/:taskId/run", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.RunTask)
+					taskQueueRouter.DELETE("/tasks/:taskId", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.DeleteTask)
+					taskQueueRouter.POST("/archived/run", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.RunAllArchivedTasks)
+					taskQueueRouter.DELETE("/archived", admin.RequirePermission(model.PermissionTaskQueueWrite), task_queue.RequireQueue(), task_queue.DeleteAllArchivedTasks)
 				}
 			}
 		}
@@ -227,14 +349,34 @@ func Serve() {
 
 	expireListenerCtx, expireListenerCancel := context.WithCancel(context.Background())
 
-	if err := listener.StartExpireListener(expireListenerCtx); err != nil {
-		log.Fatalf("[API] 警告: 启动过期监听器失败: %v\n", err)
-	}
+	// 订阅缓存失效广播，其他实例的写入提交后即时失效本实例缓存
+	cache.StartInvalidationListener(expireListenerCtx)
+
+	// 过期监听只在 leader 上运行，避免多个副本重复处理同一过期事件
+	// 过期监听仅用于加速，订单到期由任务队列保证，托管 Redis 禁用 CONFIG SET 时不影响启动
+	expireElector := leader.New(listener.ElectionName, leader.DefaultLeaseTTL)
+	expireElectionDone := make(chan struct{})
+	go func() {
+		defer close(expireElectionDone)
+		if db.Redis == nil {
+			return
+		}
+		expireElector.Run(expireListenerCtx, func(leaderCtx context.Context, fence int64) {
+			guard := func(ctx context.Context) error {
+				return expireElector.Verify(ctx, fence)
+			}
+			if err := listener.StartExpireListener(leaderCtx, guard); err != nil {
+				log.Printf("[API] 警告: 启动过期监听器失败，将仅依赖到期任务: %v\n", err)
+			}
+			<-leaderCtx.Done()
+		})
+	}()
 
 	srv := &http.Server{
 		Addr:    config.Config.App.Addr,
 		Handler: r,
 	}
+	srv.RegisterOnShutdown(payment.CloseOrderEventStreams)
 
 	go func() {
 		log.Printf("[API] server starting on %s\n", config.Config.App.Addr)
@@ -249,7 +391,13 @@ func Serve() {
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Config.App.GracefulShutdownTimeout)*time.Second)
 	defer cancel()
-	defer expireListenerCancel()
+
+	// 先停止 leader 任务并释放租约，其他副本可立即接管
+	expireListenerCancel()
+	select {
+	case <-expireElectionDone:
+	case <-shutdownCtx.Done():
+	}
 
 	otel_trace.Shutdown(shutdownCtx)
 
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+)
+
+// defaultDispatchDelay 未配置 dispatch_delay_seconds 时的分发延迟
+const defaultDispatchDelay = 3 * time.Second
+
+// PendingNotifications 业务事务内创建的通知，事务提交后调用 Dispatch 下发站外分发任务
+// 分发任务在提交前下发时，Worker 可能读不到尚未提交的通知而直接丢弃
+type PendingNotifications []*model.Notification
+
+// Create 在业务事务内创建站内通知，用户关闭了对应分类的通知时直接跳过
+// 事务可能重试，调用方应在事务函数开头重置已收集的通知
+func (p *PendingNotifications) Create(tx *gorm.DB, notification *model.Notification) error {
+	if notification.UserID == 0 {
+		return nil
+	}
+
+	var enabled []bool
+	if err := tx.Model(&model.NotificationPreference{}).
+		Where("user_id = ? AND category = ?", notification.UserID, notification.Category).
+		Pluck("enabled", &enabled).Error; err != nil {
+		return err
+	}
+	if len(enabled) > 0 && !enabled[0] {
+		return nil
+	}
+
+	if err := tx.Create(notification).Error; err != nil {
+		return err
+	}
+	*p = append(*p, notification)
+	return nil
+}
+
+// Dispatch 为已提交的通知下发站外分发任务，仅处理配置了站外渠道的用户
+// 站外通知失败不影响业务，错误只记录日志
+func (p PendingNotifications) Dispatch(ctx context.Context) {
+	if len(p) == 0 {
+		return
+	}
+
+	userIDs := make([]uint64, 0, len(p))
+	for _, notification := range p {
+		userIDs = append(userIDs, notification.UserID)
+	}
+
+	var channelUserIDs []uint64
+	if err := db.DB(db.WithPrimary(ctx)).Model(&model.UserNotificationChannel{}).
+		Where("user_id IN ? AND enabled = ?", userIDs, true).
+		Distinct().
+		Pluck("user_id", &channelUserIDs).Error; err != nil {
+		logger.ErrorF(ctx, "查询用户通知渠道失败: %v", err)
+		return
+	}
+	if len(channelUserIDs) == 0 {
+		return
+	}
+	hasChannel := make(map[uint64]bool, len(channelUserIDs))
+	for _, userID := range channelUserIDs {
+		hasChannel[userID] = true
+	}
+
+	delay := time.Duration(config.Config.Notify.DispatchDelaySeconds) * time.Second
+	if delay <= 0 {
+		delay = defaultDispatchDelay
+	}
+	for _, notification := range p {
+		if !hasChannel[notification.UserID] {
+			continue
+		}
+
+		payload, _ := json.Marshal(map[string]interface{}{
+			"notification_id": notification.ID,
+		})
+		if _, err := schedule.AsynqClient.Enqueue(
+			asynq.NewTask(task.NotificationDispatchTask, payload),
+			asynq.Queue(task.QueueNotify),
+			asynq.ProcessIn(delay),
+			asynq.MaxRetry(3),
+		); err != nil {
+			logger.ErrorF(ctx, "下发通知[ID:%d]分发任务失败: %v", notification.ID, err)
+		}
+	}
+}
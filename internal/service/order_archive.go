@@ -0,0 +1,71 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+)
+
+// ArchiveStaleOrders 将 before 之前创建且处于可归档终态的订单分批移入归档表，返回归档的订单数量
+// 每批在单条语句内完成删除与写入，按创建时间顺序处理以便只扫描最早的分区
+func ArchiveStaleOrders(ctx context.Context, before time.Time, batchSize int) (int, error) {
+	var columns []string
+	if err := db.DB(db.WithPrimary(ctx)).Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'orders'
+		ORDER BY ordinal_position`).Scan(&columns).Error; err != nil {
+		return 0, err
+	}
+	if len(columns) == 0 {
+		return 0, errors.New("读取 orders 表结构失败")
+	}
+	columnList := `"` + strings.Join(columns, `", "`) + `"`
+
+	archiveSQL := fmt.Sprintf(`WITH moved AS (
+		DELETE FROM orders
+		WHERE (id, created_at) IN (
+			SELECT id, created_at FROM orders
+			WHERE status IN ? AND created_at < ?
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		) AND status IN ?
+		RETURNING %[1]s
+	)
+	INSERT INTO %[2]s (%[1]s, archived_at)
+	SELECT %[1]s, NOW() FROM moved`, columnList, model.OrderArchiveTable)
+
+	total := 0
+	for {
+		result := db.DB(ctx).Exec(archiveSQL,
+			model.OrderArchiveStatuses, before, batchSize, model.OrderArchiveStatuses)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += int(result.RowsAffected)
+
+		if result.RowsAffected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
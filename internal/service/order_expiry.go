@@ -0,0 +1,141 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueMerchantNotify 下发商户支付成功异步回调任务
+func EnqueueMerchantNotify(orderID uint64, clientID string) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"order_id":  orderID,
+		"client_id": clientID,
+	})
+	if _, err := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.MerchantPaymentNotifyTask, payload),
+		asynq.Queue(task.QueueWebhook),
+		asynq.MaxRetry(5),
+		asynq.Timeout(30*time.Second),
+	); err != nil {
+		return fmt.Errorf("下发商户回调任务失败: %w", err)
+	}
+	return nil
+}
+
+// EnqueueOrderExpire 在订单到期时间下发过期任务，同一订单只会存在一个过期任务
+func EnqueueOrderExpire(orderID uint64, expiresAt time.Time) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"order_id": orderID,
+	})
+	if _, err := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.ExpireOrderTask, payload),
+		asynq.TaskID(fmt.Sprintf("%s:%d", task.ExpireOrderTask, orderID)),
+		asynq.ProcessAt(expiresAt),
+		asynq.MaxRetry(5),
+		asynq.Retention(time.Hour),
+	); err != nil {
+		return fmt.Errorf("下发订单过期任务失败: %w", err)
+	}
+	return nil
+}
+
+// ExpireOrder 将已到期的待支付订单置为过期，返回是否由本次调用完成过期
+// 订单已支付、已关闭或尚未到期时不做任何修改
+func ExpireOrder(ctx context.Context, orderID uint64) (bool, error) {
+	var expired []model.Order
+	if err := db.DB(ctx).Model(&expired).
+		Clauses(clause.Returning{Columns: expiredOrderColumns}).
+		Where("id = ? AND status = ? AND expires_at <= ?", orderID, model.OrderStatusPending, time.Now()).
+		Update("status", model.OrderStatusExpired).Error; err != nil {
+		return false, err
+	}
+
+	for i := range expired {
+		afterOrderExpired(ctx, &expired[i])
+	}
+	return len(expired) > 0, nil
+}
+
+// ExpireDueOrders 分批过期所有已到期的待支付订单，返回过期的订单数量
+func ExpireDueOrders(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		var expired []model.Order
+		if err := db.DB(ctx).Model(&expired).
+			Clauses(clause.Returning{Columns: expiredOrderColumns}).
+			Where("id IN (?)", db.DB(ctx).Model(&model.Order{}).
+				Select("id").
+				Where("status = ? AND expires_at <= ?", model.OrderStatusPending, time.Now()).
+				Order("id ASC").
+				Limit(batchSize).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})).
+			Where("status = ?", model.OrderStatusPending).
+			Update("status", model.OrderStatusExpired).Error; err != nil {
+			return total, err
+		}
+
+		for i := range expired {
+			afterOrderExpired(ctx, &expired[i])
+		}
+		total += len(expired)
+
+		if len(expired) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// expiredOrderColumns 过期订单需要返回的字段，用于推送状态与通知商户
+var expiredOrderColumns = []clause.Column{
+	{Name: "id"}, {Name: "client_id"}, {Name: "payee_user_id"}, {Name: "order_name"}, {Name: "merchant_order_no"}, {Name: "amount"},
+}
+
+// afterOrderExpired 订单过期后推送状态事件，并通过站内通知告知商户
+// 不回调商户 notify_url，易支付接入方通常不区分 trade_status，过期回调可能被误当作支付成功
+func afterOrderExpired(ctx context.Context, order *model.Order) {
+	logger.InfoF(ctx, "订单已过期: order_id=%d", order.ID)
+	model.PublishOrderStatus(ctx, order.ID, model.OrderStatusExpired)
+
+	if order.ClientID == "" {
+		return
+	}
+	var notifications PendingNotifications
+	if err := notifications.Create(db.DB(ctx), &model.Notification{
+		UserID:   order.PayeeUserID,
+		Category: model.NotificationCategoryOrder,
+		Type:     model.NotificationTypeOrderExpired,
+		Title:    "订单已过期",
+		Content:  fmt.Sprintf("订单「%s」（商户订单号 %s，金额 %s）超时未支付，已自动关闭", order.OrderName, order.MerchantOrderNo, order.Amount.StringFixed(2)),
+		OrderID:  &order.ID,
+	}); err != nil {
+		logger.ErrorF(ctx, "创建订单[ID:%d]过期通知失败: %v", order.ID, err)
+		return
+	}
+	notifications.Dispatch(ctx)
+}
@@ -52,6 +52,7 @@ func CheckDailyLimit(tx *gorm.DB, userID uint64, amount decimal.Decimal, dailyLi
 			[]model.OrderType{model.OrderTypePayment, model.OrderTypeOnline},
 			todayStart,
 			todayEnd).
+		Scopes(createdBeforeTradeScope(todayStart, todayEnd)).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&todayTotalAmount).Error; err != nil {
 		return err
@@ -65,6 +66,14 @@ func CheckDailyLimit(tx *gorm.DB, userID uint64, amount decimal.Decimal, dailyLi
 	return nil
 }
 
+// createdBeforeTradeScope 按交易时间范围补充 created_at 条件，使订单表可按分区裁剪
+// 订单必然先创建后支付，且只能在创建后 MaxOrderLifetime 内支付
+func createdBeforeTradeScope(tradeStart, tradeEnd time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at >= ? AND created_at < ?", tradeStart.Add(-model.MaxOrderLifetime), tradeEnd)
+	}
+}
+
 // DeductUserBalance 扣减用户余额
 // 返回 nil 表示扣减成功，返回 error 表示余额不足或更新失败
 func DeductUserBalance(tx *gorm.DB, userID uint64, amount decimal.Decimal) error {
@@ -120,6 +129,7 @@ func GetTodayUsedAmount(db *gorm.DB, userID uint64) (decimal.Decimal, error) {
 			[]model.OrderType{model.OrderTypePayment, model.OrderTypeOnline},
 			todayStart,
 			todayEnd).
+		Scopes(createdBeforeTradeScope(todayStart, todayEnd)).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&todayTotalAmount).Error; err != nil {
 		return decimal.Zero, err
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+)
+
+// ApplyOrderKeywordFilter 按关键字搜索订单名称、备注、商户订单号和对方用户名
+// 匹配使用 ILIKE，由 pg_trgm GIN 索引加速；纯数字关键字同时匹配订单号
+// userID 为查询视角的用户，对方即订单中除该用户外的另一方
+func ApplyOrderKeywordFilter(query *gorm.DB, userID uint64, keyword string) *gorm.DB {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return query
+	}
+
+	pattern := util.ContainsPattern(keyword)
+	conditions := query.Session(&gorm.Session{NewDB: true}).
+		Where("orders.order_name ILIKE ?", pattern).
+		Or("orders.remark ILIKE ?", pattern).
+		Or("orders.merchant_order_no ILIKE ?", pattern).
+		Or(`EXISTS (SELECT 1 FROM users AS counterparty WHERE counterparty.username ILIKE ?
+			AND counterparty.id = CASE WHEN orders.payer_user_id = ? THEN orders.payee_user_id ELSE orders.payer_user_id END)`,
+			pattern, userID)
+
+	if orderID, err := strconv.ParseUint(keyword, 10, 64); err == nil {
+		conditions = conditions.Or("orders.id = ?", orderID)
+	}
+
+	return query.Where(conditions)
+}
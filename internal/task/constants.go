@@ -21,11 +21,25 @@ const (
 	UpdateSingleUserGamificationScoreTask = "user:gamification:update_single_score_task"
 	AutoRefundExpiredDisputesTask         = "dispute:auto_refund_expired"
 	AutoRefundSingleDisputeTask           = "dispute:auto_refund_single"
-	MerchantPaymentNotifyTask             = "payment:merchant_notify" // 商户支付回调任务
+	RemindExpiringDisputesTask            = "dispute:remind_expiring"     // 争议超时提醒任务
+	MerchantPaymentNotifyTask             = "payment:merchant_notify"     // 商户支付回调任务
+	ExpireOrderTask                       = "payment:order:expire"        // 单个订单到期过期任务
+	SweepExpiredOrdersTask                = "payment:order:expire_sweep"  // 到期订单兜底扫描任务
+	RefreshMerchantReputationsTask        = "merchant:reputation:refresh" // 商户信誉刷新任务
+	AnalyticsRollupTask                   = "analytics:rollup"            // 统计数据日汇总任务
+	TransactionExportTask                 = "order:export"                // 交易导出任务
+	CleanupExpiredExportsTask             = "order:export:cleanup"        // 过期导出文件清理任务
+	GenerateMonthlyStatementsTask         = "user:statement:generate"     // 月结单生成调度任务
+	GenerateStatementBatchTask            = "user:statement:batch"        // 单批用户月结单生成任务
+	NotificationDispatchTask              = "notification:dispatch"       // 站外通知分发任务
+	NotificationDeliverTask               = "notification:deliver"        // 单渠道站外通知投递任务
+	ManageOrderPartitionsTask             = "order:partition:manage"      // 订单月分区维护任务
+	ArchiveOrdersTask                     = "order:archive"               // 旧订单归档任务
 )
 
 const (
 	QueueWhitelistOnly = "whitelist_only"
 	QueueWebhook       = "webhook"
+	QueueNotify        = "notify"
 	QueueDefault       = "default"
 )
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/robfig/cron/v3"
+
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/task"
+)
+
+const (
+	// DefaultTimezone 未配置时区时使用的默认时区
+	DefaultTimezone = "Asia/Shanghai"
+	// SystemConfigKeyPrefix system_configs 中周期任务覆盖项的 key 前缀，格式为 schedule.<任务名>.<字段>
+	SystemConfigKeyPrefix = "schedule."
+)
+
+// ErrPeriodicTaskNotFound 周期任务不存在
+var ErrPeriodicTaskNotFound = errors.New("periodic task not found")
+
+// system_configs 中支持覆盖的字段
+const (
+	overrideFieldCron    = "cron"
+	overrideFieldEnabled = "enabled"
+)
+
+// PeriodicTask 周期任务定义
+type PeriodicTask struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Cron        string        `json:"cron"`
+	Queue       string        `json:"queue"`
+	Unique      time.Duration `json:"-"`
+	Enabled     bool          `json:"enabled"`
+}
+
+// NextRun 计算指定时区下 from 之后的下一次执行时间
+func (p *PeriodicTask) NextRun(from time.Time, location *time.Location) (time.Time, error) {
+	sched, err := cron.ParseStandard(p.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(from.In(location)), nil
+}
+
+// Options 注册与手动触发时使用的任务选项
+func (p *PeriodicTask) Options() []asynq.Option {
+	opts := []asynq.Option{asynq.Queue(p.Queue)}
+	if p.Unique > 0 {
+		opts = append(opts, asynq.Unique(p.Unique))
+	}
+	return opts
+}
+
+// defaultPeriodicTasks 内置周期任务，cron 表达式沿用 schedule 下各任务的独立配置项
+func defaultPeriodicTasks() []PeriodicTask {
+	cfg := config.Config.Schedule
+	return []PeriodicTask{
+		{Name: task.SweepExpiredOrdersTask, Description: "到期订单兜底扫描", Cron: cfg.SweepExpiredOrdersTaskCron, Unique: 50 * time.Second},
+		{Name: task.UpdateUserGamificationScoresTask, Description: "用户积分更新", Cron: cfg.UpdateUserGamificationScoresTaskCron, Unique: 23 * time.Hour},
+		{Name: task.AutoRefundExpiredDisputesTask, Description: "争议自动退款", Cron: cfg.AutoRefundExpiredDisputesTaskCron, Unique: 23 * time.Hour},
+		{Name: task.RemindExpiringDisputesTask, Description: "争议超时提醒", Cron: cfg.RemindExpiringDisputesTaskCron, Unique: 50 * time.Minute},
+		{Name: task.RefreshMerchantReputationsTask, Description: "商户信誉刷新", Cron: cfg.RefreshMerchantReputationsTaskCron, Unique: 23 * time.Hour},
+		{Name: task.AnalyticsRollupTask, Description: "统计数据日汇总", Cron: cfg.AnalyticsRollupTaskCron, Unique: 50 * time.Minute},
+		{Name: task.CleanupExpiredExportsTask, Description: "过期导出文件清理", Cron: cfg.CleanupExpiredExportsTaskCron, Unique: 50 * time.Minute},
+		{Name: task.GenerateMonthlyStatementsTask, Description: "月结单生成", Cron: cfg.GenerateMonthlyStatementsTaskCron, Unique: 23 * time.Hour},
+		{Name: task.ManageOrderPartitionsTask, Description: "订单月分区维护", Cron: cfg.ManageOrderPartitionsTaskCron, Unique: 23 * time.Hour},
+		{Name: task.ArchiveOrdersTask, Description: "旧订单归档", Cron: cfg.ArchiveOrdersTaskCron, Unique: 23 * time.Hour},
+	}
+}
+
+// Location 定时任务使用的时区
+func Location() (*time.Location, error) {
+	name := config.Config.Schedule.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// PeriodicTasks 加载周期任务列表：内置定义 -> 配置文件覆盖 -> system_configs 覆盖
+func PeriodicTasks(ctx context.Context) ([]PeriodicTask, error) {
+	tasks := defaultPeriodicTasks()
+	for i := range tasks {
+		tasks[i].Queue = task.QueueDefault
+		tasks[i].Enabled = true
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i := range tasks {
+		index[tasks[i].Name] = i
+	}
+
+	for _, override := range config.Config.Schedule.Tasks {
+		i, ok := index[override.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown periodic task %q in config", override.Name)
+		}
+		if override.Cron != "" {
+			tasks[i].Cron = override.Cron
+		}
+		if override.Queue != "" {
+			tasks[i].Queue = override.Queue
+		}
+		if override.UniqueSeconds > 0 {
+			tasks[i].Unique = time.Duration(override.UniqueSeconds) * time.Second
+		}
+		if override.Enabled != nil {
+			tasks[i].Enabled = *override.Enabled
+		}
+	}
+
+	overrides, err := loadSystemConfigOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range overrides {
+		name, field, ok := parseOverrideKey(key)
+		if !ok {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			continue
+		}
+		switch field {
+		case overrideFieldCron:
+			tasks[i].Cron = value
+		case overrideFieldEnabled:
+			enabled, errParse := strconv.ParseBool(value)
+			if errParse != nil {
+				return nil, fmt.Errorf("invalid system config %s=%q: %w", key, value, errParse)
+			}
+			tasks[i].Enabled = enabled
+		}
+	}
+
+	for i := range tasks {
+		if _, errParse := cron.ParseStandard(tasks[i].Cron); errParse != nil {
+			return nil, fmt.Errorf("invalid cron %q for periodic task %s: %w", tasks[i].Cron, tasks[i].Name, errParse)
+		}
+	}
+
+	return tasks, nil
+}
+
+// FindPeriodicTask 按任务名查找周期任务
+func FindPeriodicTask(ctx context.Context, name string) (*PeriodicTask, error) {
+	tasks, err := PeriodicTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].Name == name {
+			return &tasks[i], nil
+		}
+	}
+	return nil, ErrPeriodicTaskNotFound
+}
+
+// RunNow 立即投递一次周期任务，不受 enabled 与唯一性约束影响
+func RunNow(ctx context.Context, p *PeriodicTask) (*asynq.TaskInfo, error) {
+	return AsynqClient.EnqueueContext(ctx, asynq.NewTask(p.Name, nil), asynq.Queue(p.Queue))
+}
+
+// loadSystemConfigOverrides 读取 system_configs 中 schedule. 前缀的覆盖项
+func loadSystemConfigOverrides(ctx context.Context) (map[string]string, error) {
+	if !config.Config.Database.Enabled {
+		return nil, nil
+	}
+
+	var rows []struct {
+		Key   string
+		Value string
+	}
+	if err := db.DB(ctx).
+		Table("system_configs").
+		Select("key, value").
+		Where("key LIKE ?", SystemConfigKeyPrefix+"%").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load schedule overrides: %w", err)
+	}
+
+	overrides := make(map[string]string, len(rows))
+	for _, row := range rows {
+		overrides[row.Key] = row.Value
+	}
+	return overrides, nil
+}
+
+// parseOverrideKey 解析 schedule.<任务名>.<字段>，以最后一个 . 分隔任务名与字段
+func parseOverrideKey(key string) (name string, field string, ok bool) {
+	rest := strings.TrimPrefix(key, SystemConfigKeyPrefix)
+	idx := strings.LastIndex(rest, ".")
+	if idx <= 0 || idx == len(rest)-1 {
+		return "", "", false
+	}
+	return rest[:idx], rest[idx+1:], true
+}
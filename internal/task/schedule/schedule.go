@@ -17,11 +17,11 @@ limitations under the License.
 package schedule
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"sync"
-	"time"
 
-	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/task"
 
 	"github.com/hibiken/asynq"
@@ -37,13 +37,13 @@ func init() {
 	AsynqClient = asynq.NewClient(task.RedisOpt)
 }
 
-// StartScheduler 启动调度器
+// StartScheduler 启动调度器，按周期任务注册表注册所有启用的任务
 func StartScheduler() error {
 	var err error
 	schedulerOnce.Do(func() {
-		location, locErr := time.LoadLocation("Asia/Shanghai")
+		location, locErr := Location()
 		if locErr != nil {
-			err = fmt.Errorf("failed to load location: %w", locErr)
+			err = locErr
 			return
 		}
 		scheduler = asynq.NewScheduler(
@@ -53,22 +53,25 @@ func StartScheduler() error {
 			},
 		)
 
-		// 用户积分更新任务
-		if _, err = scheduler.Register(
-			config.Config.Schedule.UpdateUserGamificationScoresTaskCron,
-			asynq.NewTask(task.UpdateUserGamificationScoresTask, nil),
-			asynq.Unique(23*time.Hour),
-		); err != nil {
+		tasks, loadErr := PeriodicTasks(context.Background())
+		if loadErr != nil {
+			err = loadErr
 			return
 		}
 
-		// 争议自动退款任务
-		if _, err = scheduler.Register(
-			config.Config.Schedule.AutoRefundExpiredDisputesTaskCron,
-			asynq.NewTask(task.AutoRefundExpiredDisputesTask, nil),
-			asynq.Unique(23*time.Hour),
-		); err != nil {
-			return
+		for i := range tasks {
+			if !tasks[i].Enabled {
+				log.Printf("[Scheduler] 周期任务 %s 已禁用，跳过注册\n", tasks[i].Name)
+				continue
+			}
+			if _, err = scheduler.Register(
+				tasks[i].Cron,
+				asynq.NewTask(tasks[i].Name, nil),
+				tasks[i].Options()...,
+			); err != nil {
+				err = fmt.Errorf("failed to register periodic task %s: %w", tasks[i].Name, err)
+				return
+			}
 		}
 
 		// 启动调度器
@@ -17,12 +17,20 @@ limitations under the License.
 package worker
 
 import (
+	"context"
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/apps/analytics"
 	"github.com/linux-do/pay/internal/apps/dispute"
+	"github.com/linux-do/pay/internal/apps/merchant/reputation"
+	"github.com/linux-do/pay/internal/apps/order"
+	"github.com/linux-do/pay/internal/apps/order/export"
 	"github.com/linux-do/pay/internal/apps/payment"
 	"github.com/linux-do/pay/internal/apps/user"
+	"github.com/linux-do/pay/internal/apps/user/notification"
+	"github.com/linux-do/pay/internal/apps/user/statement"
+	"github.com/linux-do/pay/internal/cache"
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/task"
 )
@@ -40,13 +48,29 @@ func StartWorker() error {
 	)
 
 	// 注册任务处理器
+	// 订阅缓存失效广播，任务中读取的支付等级配置随管理端修改即时失效
+	cache.StartInvalidationListener(context.Background())
+
 	mux := asynq.NewServeMux()
 	mux.Use(taskLoggingMiddleware)
 	mux.HandleFunc(task.UpdateUserGamificationScoresTask, user.HandleUpdateUserGamificationScores)
 	mux.HandleFunc(task.UpdateSingleUserGamificationScoreTask, user.HandleUpdateSingleUserGamificationScore)
 	mux.HandleFunc(task.AutoRefundExpiredDisputesTask, dispute.HandleAutoRefundExpiredDisputes)
 	mux.HandleFunc(task.AutoRefundSingleDisputeTask, dispute.HandleAutoRefundSingleDispute)
+	mux.HandleFunc(task.RemindExpiringDisputesTask, dispute.HandleRemindExpiringDisputes)
 	mux.HandleFunc(task.MerchantPaymentNotifyTask, payment.HandleMerchantPaymentNotify)
+	mux.HandleFunc(task.ExpireOrderTask, payment.HandleExpireOrder)
+	mux.HandleFunc(task.SweepExpiredOrdersTask, payment.HandleSweepExpiredOrders)
+	mux.HandleFunc(task.RefreshMerchantReputationsTask, reputation.HandleRefreshMerchantReputations)
+	mux.HandleFunc(task.AnalyticsRollupTask, analytics.HandleAnalyticsRollup)
+	mux.HandleFunc(task.TransactionExportTask, export.HandleTransactionExport)
+	mux.HandleFunc(task.CleanupExpiredExportsTask, export.HandleCleanupExpiredExports)
+	mux.HandleFunc(task.GenerateMonthlyStatementsTask, statement.HandleGenerateMonthlyStatements)
+	mux.HandleFunc(task.GenerateStatementBatchTask, statement.HandleGenerateStatementBatch)
+	mux.HandleFunc(task.NotificationDispatchTask, notification.HandleDispatchNotification)
+	mux.HandleFunc(task.NotificationDeliverTask, notification.HandleDeliverNotification)
+	mux.HandleFunc(task.ManageOrderPartitionsTask, order.HandleManageOrderPartitions)
+	mux.HandleFunc(task.ArchiveOrdersTask, order.HandleArchiveOrders)
 	// 启动服务器
 	return asynqServer.Run(mux)
 }
@@ -69,6 +93,7 @@ func buildQueuesFromConfig() map[string]int {
 		queues = map[string]int{
 			task.QueueWebhook:       10,
 			task.QueueWhitelistOnly: 5,
+			task.QueueNotify:        4,
 			task.QueueDefault:       1,
 		}
 	}
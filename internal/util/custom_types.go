@@ -36,3 +36,39 @@ func (sa *StringArray) Scan(value interface{}) error {
 func (sa StringArray) Value() (driver.Value, error) {
 	return json.Marshal(sa)
 }
+
+// RawJSON custom type for handling raw JSON documents
+type RawJSON []byte
+
+func (rj *RawJSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*rj = nil
+	case []byte:
+		*rj = append((*rj)[:0], v...)
+	case string:
+		*rj = RawJSON(v)
+	default:
+		return fmt.Errorf("invalid value: %v", value)
+	}
+	return nil
+}
+
+func (rj RawJSON) Value() (driver.Value, error) {
+	if len(rj) == 0 {
+		return nil, nil
+	}
+	return string(rj), nil
+}
+
+func (rj RawJSON) MarshalJSON() ([]byte, error) {
+	if len(rj) == 0 {
+		return []byte("null"), nil
+	}
+	return rj, nil
+}
+
+func (rj *RawJSON) UnmarshalJSON(data []byte) error {
+	*rj = append((*rj)[:0], data...)
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvalidCursor 游标无法解析
+const InvalidCursor = "分页游标无效"
+
+// Cursor keyset 分页游标，按 (created_at, id) 倒序定位上一页的最后一条记录
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint64    `json:"i"`
+}
+
+// EncodeCursor 将游标编码为不透明字符串
+func EncodeCursor(createdAt time.Time, id uint64) string {
+	data, _ := json.Marshal(Cursor{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析不透明游标字符串
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New(InvalidCursor)
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, errors.New(InvalidCursor)
+	}
+	return &cursor, nil
+}
+
+// PageRequest 通用分页参数
+// page 为空或传入 cursor 时使用游标分页，首页 cursor 留空；游标模式下仅在 with_total 为 true 时统计总数
+type PageRequest struct {
+	Page      int    `json:"page" form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Cursor    string `json:"cursor" form:"cursor" binding:"omitempty,max=256"`
+	WithTotal bool   `json:"with_total" form:"with_total"`
+}
+
+// PageResponse 通用分页响应字段，游标模式下 total 为空表示未统计
+type PageResponse struct {
+	Total      *int64 `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// IsCursorMode 是否使用游标分页
+func (p *PageRequest) IsCursorMode() bool {
+	return p.Page == 0 || p.Cursor != ""
+}
+
+// NeedTotal 是否需要统计总数，分页模式始终统计以保持兼容
+func (p *PageRequest) NeedTotal() bool {
+	return !p.IsCursorMode() || p.WithTotal
+}
+
+// Paginate 为查询追加 (created_at, id) 倒序排序与分页条件
+// 两种模式都带 created_at 上界以便按分区裁剪：分页模式以请求时间为上界，跳过预建的未来分区；游标模式以游标为上界
+// 游标模式多取一条记录用于判断是否还有下一页
+func (p *PageRequest) Paginate(query *gorm.DB, table string) (*gorm.DB, error) {
+	query = query.Order(table + ".created_at DESC").Order(table + ".id DESC")
+	if !p.IsCursorMode() {
+		return query.Where(table+".created_at <= ?", time.Now()).
+			Offset((p.Page - 1) * p.PageSize).Limit(p.PageSize), nil
+	}
+
+	if p.Cursor != "" {
+		cursor, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// 单独的 created_at 条件用于分区裁剪，行比较条件无法裁剪分区
+		query = query.Where(table+".created_at <= ? AND ("+table+".created_at, "+table+".id) < (?, ?)",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+	return query.Limit(p.PageSize + 1), nil
+}
+
+// BuildPageResponse 根据查询结果生成分页响应，游标模式下截掉多取的一条并生成下一页游标
+func BuildPageResponse[T any](p *PageRequest, total *int64, items *[]T, key func(*T) (time.Time, uint64)) PageResponse {
+	response := PageResponse{Total: total, PageSize: p.PageSize}
+	if !p.IsCursorMode() {
+		response.Page = p.Page
+		response.HasMore = total != nil && int64(p.Page*p.PageSize) < *total
+		return response
+	}
+
+	if len(*items) > p.PageSize {
+		*items = (*items)[:p.PageSize]
+		response.HasMore = true
+	}
+	if response.HasMore && len(*items) > 0 {
+		createdAt, id := key(&(*items)[len(*items)-1])
+		response.NextCursor = EncodeCursor(createdAt, id)
+	}
+	return response
+}
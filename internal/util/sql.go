@@ -0,0 +1,31 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike 转义 LIKE/ILIKE 模式中的通配符
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// ContainsPattern 构造包含匹配的 LIKE/ILIKE 模式
+func ContainsPattern(s string) string {
+	return "%" + EscapeLike(s) + "%"
+}